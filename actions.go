@@ -0,0 +1,87 @@
+package cablemodemutil
+
+import (
+	"context"
+	"fmt"
+)
+
+// ActionResponse contains the unpacked response to a SOAP action.
+type ActionResponse map[string]interface{}
+
+// Do sends the specified SOAP action containing the specified payload to the
+// cable modem within an authenticated session (logging in if required), and
+// returns the unpacked response after verifying the result is "OK". Fails
+// with ErrUnsupported if the driver does not implement ActionDriver.
+func (r *Retriever) Do(ctx context.Context, action string, payload map[string]string) (ActionResponse, error) {
+	d, err := r.actionDriver()
+	if err != nil {
+		return nil, err
+	}
+	return d.Do(ctx, action, payload)
+}
+
+// GetMultiple queries the specified actions from the cable modem in a single
+// GetMultipleHNAPs request within an authenticated session (logging in if
+// required). Returns the unpacked responses keyed by the action, after
+// verifying the result of each of the actions is "OK". Fails with
+// ErrUnsupported if the driver does not implement ActionDriver.
+func (r *Retriever) GetMultiple(ctx context.Context, actions ...string) (map[string]ActionResponse, error) {
+	d, err := r.actionDriver()
+	if err != nil {
+		return nil, err
+	}
+	return d.GetMultiple(ctx, actions...)
+}
+
+// SetFrontPanelLights turns the front panel LED lights of the cable modem on
+// or off. The rest of the configurable settings are retained as is. Fails
+// with ErrUnsupported if the driver does not implement ConfigurationDriver.
+func (r *Retriever) SetFrontPanelLights(ctx context.Context, on bool) error {
+	d, err := r.configurationDriver()
+	if err != nil {
+		return err
+	}
+	return d.SetFrontPanelLights(ctx, on)
+}
+
+// SetEnergyEfficientEthernet turns the energy efficient ethernet setting of
+// the cable modem on or off. The rest of the configurable settings are
+// retained as is. Fails with ErrUnsupported if the driver does not
+// implement ConfigurationDriver.
+func (r *Retriever) SetEnergyEfficientEthernet(ctx context.Context, on bool) error {
+	d, err := r.configurationDriver()
+	if err != nil {
+		return err
+	}
+	return d.SetEnergyEfficientEthernet(ctx, on)
+}
+
+// Reboot reboots the cable modem. The configurable settings are retained as
+// is. The current session is discarded since it does not survive the
+// reboot, and the next request logs in again. Fails with ErrUnsupported if
+// the driver does not implement ConfigurationDriver.
+func (r *Retriever) Reboot(ctx context.Context) error {
+	d, err := r.configurationDriver()
+	if err != nil {
+		return err
+	}
+	return d.Reboot(ctx)
+}
+
+// Returns the driver as an ActionDriver if supported.
+func (r *Retriever) actionDriver() (ActionDriver, error) {
+	d, ok := r.driver.(ActionDriver)
+	if !ok {
+		return nil, fmt.Errorf("%w: raw SOAP actions", ErrUnsupported)
+	}
+	return d, nil
+}
+
+// Returns the driver as a ConfigurationDriver if supported.
+func (r *Retriever) configurationDriver() (ConfigurationDriver, error) {
+	d, ok := r.driver.(ConfigurationDriver)
+	if !ok {
+		return nil, fmt.Errorf("%w: configuration changes", ErrUnsupported)
+	}
+	return d, nil
+}
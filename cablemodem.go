@@ -6,6 +6,83 @@ import "time"
 // CableModemRawStatus contains the raw status retrieved from the cable modem without any parsing.
 type CableModemRawStatus map[string]interface{}
 
+// StatusSection identifies a section of the Cable Modem status. Sections can
+// be combined as a bitmask.
+type StatusSection uint32
+
+const (
+	// SectionInfo identifies the device information in the status.
+	SectionInfo StatusSection = 1 << iota
+	// SectionSettings identifies the device settings in the status.
+	SectionSettings
+	// SectionAuth identifies the auth settings in the status.
+	SectionAuth
+	// SectionSoftware identifies the software status in the status.
+	SectionSoftware
+	// SectionStartup identifies the startup status in the status.
+	SectionStartup
+	// SectionConnection identifies the connection status in the status,
+	// excluding the downstream and upstream channel information.
+	SectionConnection
+	// SectionDownstreamChannels identifies the downstream channel
+	// information in the connection status.
+	SectionDownstreamChannels
+	// SectionUpstreamChannels identifies the upstream channel information
+	// in the connection status.
+	SectionUpstreamChannels
+	// SectionLogs identifies the log entries in the status.
+	SectionLogs
+	// SectionOFDMDownstreamChannels identifies the DOCSIS 3.1 OFDM
+	// downstream channel information in the connection status. Only
+	// available on cable modems with firmware supporting the corresponding
+	// sub-command, and hence not part of DefaultSections.
+	SectionOFDMDownstreamChannels
+	// SectionOFDMAUpstreamChannels identifies the DOCSIS 3.1 OFDMA upstream
+	// channel information in the connection status. Only available on
+	// cable modems with firmware supporting the corresponding sub-command,
+	// and hence not part of DefaultSections.
+	SectionOFDMAUpstreamChannels
+
+	// DefaultSections identifies all the sections retrieved when no sections
+	// are specified explicitly.
+	DefaultSections = SectionInfo |
+		SectionSettings |
+		SectionAuth |
+		SectionSoftware |
+		SectionStartup |
+		SectionConnection |
+		SectionDownstreamChannels |
+		SectionUpstreamChannels |
+		SectionLogs
+
+	// AllSections identifies all the sections.
+	AllSections = DefaultSections |
+		SectionOFDMDownstreamChannels |
+		SectionOFDMAUpstreamChannels
+)
+
+// Has returns true if all the specified sections are included, false otherwise.
+func (s StatusSection) Has(sections StatusSection) bool {
+	return s&sections == sections
+}
+
+// StatusOptions contains the options for retrieving and parsing the status.
+type StatusOptions struct {
+	// The sections of the status to retrieve and parse. The rest of the
+	// sections in the status are left as zero values. DefaultSections is
+	// used if unspecified.
+	Sections StatusSection
+}
+
+// Returns the sections specified in the options, or the default sections if
+// unspecified.
+func (o *StatusOptions) sections() StatusSection {
+	if o == nil || o.Sections == 0 {
+		return DefaultSections
+	}
+	return o.Sections
+}
+
 // DeviceInfo contains Cable Modem Device information.
 type DeviceInfo struct {
 	// Cable Modem model.
@@ -110,10 +187,40 @@ type StartupStatus struct {
 	Security SecurityStatus
 }
 
+// ChannelType identifies the type of a Cable Modem channel.
+type ChannelType int
+
+const (
+	// ChannelTypeUnknown identifies a channel whose type could not be determined.
+	ChannelTypeUnknown ChannelType = iota
+	// ChannelTypeSCQAM identifies a single carrier QAM channel (DOCSIS 3.0 and earlier).
+	ChannelTypeSCQAM
+	// ChannelTypeOFDM identifies a DOCSIS 3.1 OFDM downstream channel.
+	ChannelTypeOFDM
+	// ChannelTypeOFDMA identifies a DOCSIS 3.1 OFDMA upstream channel.
+	ChannelTypeOFDMA
+)
+
+// String returns the string representation of the channel type.
+func (c ChannelType) String() string {
+	switch c {
+	case ChannelTypeSCQAM:
+		return "SC-QAM"
+	case ChannelTypeOFDM:
+		return "OFDM"
+	case ChannelTypeOFDMA:
+		return "OFDMA"
+	case ChannelTypeUnknown:
+	}
+	return "Unknown"
+}
+
 // DownstreamChannelInfo contains Cable Modem Downstream channel information.
 type DownstreamChannelInfo struct {
 	// Lock status.
 	Locked bool
+	// Type of the channel, determined based on the modulation.
+	Type ChannelType
 	// Modulation.
 	Modulation string
 	// Channel ID.
@@ -134,6 +241,8 @@ type DownstreamChannelInfo struct {
 type UpstreamChannelInfo struct {
 	// Lock status.
 	Locked bool
+	// Type of the channel, determined based on the modulation.
+	Type ChannelType
 	// Modulation.
 	Modulation string
 	// Channel ID.
@@ -146,6 +255,58 @@ type UpstreamChannelInfo struct {
 	SignalPowerDBMV float32
 }
 
+// OFDMDownstreamChannelInfo contains Cable Modem DOCSIS 3.1 OFDM Downstream
+// channel information.
+type OFDMDownstreamChannelInfo struct {
+	// Lock status.
+	Locked bool
+	// Channel ID.
+	ChannelID uint32
+	// FFT type (Eg. "4K" or "8K").
+	FFTType string
+	// Frequency of the subcarrier zero in Hz.
+	SubcarrierZeroFrequencyHZ float32
+	// Frequency of the PHY Link Channel (PLC) in Hz.
+	PLCFrequencyHZ float32
+	// First active subcarrier.
+	FirstActiveSubcarrier uint32
+	// Last active subcarrier.
+	LastActiveSubcarrier uint32
+	// Modulation profile IDs.
+	ProfileIDs []uint32
+	// Signal Power in dB mV.
+	SignalPowerDBMV float32
+	// MER of the pilots in dB.
+	MERPilotDB float32
+	// MER of the PHY Link Channel (PLC) in dB.
+	MERPLCDB float32
+	// MER of the data subcarriers in dB.
+	MERDataDB float32
+	// Corrected errors.
+	CorrectedErrors uint32
+	// Uncorrected errors.
+	UncorrectedErrors uint32
+}
+
+// OFDMAUpstreamChannelInfo contains Cable Modem DOCSIS 3.1 OFDMA Upstream
+// channel information.
+type OFDMAUpstreamChannelInfo struct {
+	// Lock status.
+	Locked bool
+	// Channel ID.
+	ChannelID uint32
+	// FFT type (Eg. "2K" or "4K").
+	FFTType string
+	// Frequency of the subcarrier zero in Hz.
+	SubcarrierZeroFrequencyHZ float32
+	// First active subcarrier.
+	FirstActiveSubcarrier uint32
+	// Last active subcarrier.
+	LastActiveSubcarrier uint32
+	// Transmit Signal Power in dB mV.
+	SignalPowerDBMV float32
+}
+
 // DownstreamConnectionStatus contains Cable Modem Connection status
 // pertaining to the downstream channels of the connection.
 type DownstreamConnectionStatus struct {
@@ -159,6 +320,8 @@ type DownstreamConnectionStatus struct {
 	SignalSNRDB float32
 	// Downstream channel information.
 	Channels []DownstreamChannelInfo
+	// DOCSIS 3.1 OFDM downstream channel information.
+	OFDMChannels []OFDMDownstreamChannelInfo
 }
 
 // UpstreamConnectionStatus contains Cable Modem Connection status
@@ -168,6 +331,8 @@ type UpstreamConnectionStatus struct {
 	ChannelID uint32
 	// Upstream channel information.
 	Channels []UpstreamChannelInfo
+	// DOCSIS 3.1 OFDMA upstream channel information.
+	OFDMAChannels []OFDMAUpstreamChannelInfo
 }
 
 // ConnectionStatus contains Cable Modem Connection status.
@@ -180,22 +345,142 @@ type ConnectionStatus struct {
 	DOCSISNetworkAccessAllowed bool
 	// Internet connection status.
 	InternetConnected bool
+	// MAC address of the CMTS serving the cable modem, parsed from the
+	// newest log entry carrying it. Empty if unavailable or if the logs
+	// were not retrieved.
+	CMTSMACAddress string
+	// QoS mode (CM-QOS) parsed from the newest log entry carrying the CMTS
+	// MAC address.
+	CMQOS string
+	// DOCSIS version (CM-VER) parsed from the newest log entry carrying the
+	// CMTS MAC address.
+	CMVersion string
 	// Downstream connection status.
 	Downstream DownstreamConnectionStatus
 	// Upstream connection status.
 	Upstream UpstreamConnectionStatus
 }
 
+// LogPriority identifies the DOCSIS event priority of a Cable Modem Log entry.
+type LogPriority uint32
+
+const (
+	// LogPriorityUnknown identifies an unknown priority.
+	LogPriorityUnknown LogPriority = iota
+	// LogPriorityEmergency identifies an emergency event.
+	LogPriorityEmergency
+	// LogPriorityAlert identifies an alert event.
+	LogPriorityAlert
+	// LogPriorityCritical identifies a critical event.
+	LogPriorityCritical
+	// LogPriorityError identifies an error event.
+	LogPriorityError
+	// LogPriorityWarning identifies a warning event.
+	LogPriorityWarning
+	// LogPriorityNotice identifies a notice event.
+	LogPriorityNotice
+	// LogPriorityInformational identifies an informational event.
+	LogPriorityInformational
+	// LogPriorityDebug identifies a debug event.
+	LogPriorityDebug
+)
+
+// String returns the string representation of the log priority.
+func (p LogPriority) String() string {
+	switch p {
+	case LogPriorityEmergency:
+		return "Emergency"
+	case LogPriorityAlert:
+		return "Alert"
+	case LogPriorityCritical:
+		return "Critical"
+	case LogPriorityError:
+		return "Error"
+	case LogPriorityWarning:
+		return "Warning"
+	case LogPriorityNotice:
+		return "Notice"
+	case LogPriorityInformational:
+		return "Informational"
+	case LogPriorityDebug:
+		return "Debug"
+	case LogPriorityUnknown:
+	}
+	return "Unknown"
+}
+
+// LogEventType identifies the kind of event in a Cable Modem Log entry.
+type LogEventType int
+
+const (
+	// LogEventUnknown identifies an event which is not one of the known kinds.
+	LogEventUnknown LogEventType = iota
+	// LogEventRangingNoResponse identifies no response received for the
+	// unicast maintenance ranging request (T3 time-out).
+	LogEventRangingNoResponse
+	// LogEventRangingT4Timeout identifies no unicast maintenance opportunities
+	// received (T4 time-out).
+	LogEventRangingT4Timeout
+	// LogEventPowerExceedsTopOfDRW identifies the CCAP commanded power
+	// exceeding the top of the dynamic range window.
+	LogEventPowerExceedsTopOfDRW
+	// LogEventDynamicRangeWindowViolation identifies a dynamic range window
+	// violation.
+	LogEventDynamicRangeWindowViolation
+	// LogEventSyncFailure identifies a timing synchronization failure.
+	LogEventSyncFailure
+	// LogEventLoginSuccess identifies a successful login attempt.
+	LogEventLoginSuccess
+	// LogEventLoginFailure identifies a failed login attempt.
+	LogEventLoginFailure
+)
+
+// String returns the string representation of the log event type.
+func (e LogEventType) String() string {
+	switch e {
+	case LogEventRangingNoResponse:
+		return "STARTED_UNICAST_MAINTENANCE_RANGING_NO_RESPONSE_RECEIVED"
+	case LogEventRangingT4Timeout:
+		return "NO_UNICAST_MAINTENANCE_OPPORTUNITIES_RECEIVED"
+	case LogEventPowerExceedsTopOfDRW:
+		return "RNG_RSP_CCAP_COMMAND_POWER_EXCEEDS_TOP_OF_DRW"
+	case LogEventDynamicRangeWindowViolation:
+		return "DYNAMIC_RANGE_WINDOW_VIOLATION"
+	case LogEventSyncFailure:
+		return "TIMING_SYNCHRONIZATION_FAILURE"
+	case LogEventLoginSuccess:
+		return "LOGIN_SUCCESS"
+	case LogEventLoginFailure:
+		return "LOGIN_FAILURE"
+	case LogEventUnknown:
+	}
+	return "UNKNOWN"
+}
+
 // LogEntry contains Cable Modem Log entry.
 type LogEntry struct {
 	// Timestamp for this log entry.
 	Timestamp time.Time
 	// The log string in the entry.
 	Log string
+	// Severity class of the entry as reported by the cable modem.
+	Severity uint32
+	// DOCSIS event priority of the entry.
+	Priority LogPriority
+	// Kind of the event in the entry.
+	Event LogEventType
+	// The log message excluding the trailing key-value attributes.
+	Message string
+	// Key-value attributes at the end of the log string (Eg. CM-MAC,
+	// CMTS-MAC, CM-QOS and CM-VER).
+	Attributes map[string]string
 }
 
 // CableModemStatus contains detailed status of the Cable Modem.
 type CableModemStatus struct {
+	// Sections available in this status, the rest of the sections are
+	// absent and left as zero values.
+	Sections StatusSection
 	// Device related information.
 	Info DeviceInfo
 	// General settings.
@@ -210,4 +495,6 @@ type CableModemStatus struct {
 	Connection ConnectionStatus
 	// Logs.
 	Logs []LogEntry
+	// Number of log entries for each kind of event.
+	LogEventCounts map[LogEventType]uint32
 }
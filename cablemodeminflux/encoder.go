@@ -0,0 +1,269 @@
+// Package cablemodeminflux encodes the cable modem status into points in the
+// InfluxDB line protocol, suitable for writing to InfluxDB directly or via
+// Telegraf.
+//
+//	st, err := cm.Status()
+//	...
+//	err = cablemodeminflux.NewEncoder(os.Stdout, nil).Encode(st, time.Now())
+package cablemodeminflux
+
+import (
+	"bufio"
+	"fmt"
+	"hash/fnv"
+	"io"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/tuxdude/cablemodemutil"
+)
+
+const defaultPrefix = "cablemodem"
+
+// Names of the measurements, which are prefixed with the configured prefix.
+const (
+	deviceMeasurement     = "device"
+	downstreamMeasurement = "downstream"
+	upstreamMeasurement   = "upstream"
+	logMeasurement        = "log"
+)
+
+// Escapes the measurement names.
+// nolint:gochecknoglobals
+var measurementEscaper = strings.NewReplacer(`,`, `\,`, ` `, `\ `, "\n", `\n`)
+
+// Escapes the tag keys, tag values and field keys.
+// nolint:gochecknoglobals
+var keyEscaper = strings.NewReplacer(`,`, `\,`, `=`, `\=`, ` `, `\ `, "\n", `\n`)
+
+// Escapes the string field values.
+// nolint:gochecknoglobals
+var stringFieldEscaper = strings.NewReplacer(`\`, `\\`, `"`, `\"`)
+
+// Options is used to specify the options of the Encoder.
+type Options struct {
+	// Prefix for the measurement names, defaults to "cablemodem".
+	Prefix string
+}
+
+// Encoder writes the cable modem status as points in the InfluxDB line
+// protocol. The following measurements are written:
+//
+//	<prefix>_device: one point tagged by the model, serial number and MAC
+//	    address, with the uptime, connectivity and firmware version fields.
+//	<prefix>_downstream: one point per downstream channel tagged by the
+//	    channel ID, modulation and lock state, with the frequency, signal and
+//	    error counter fields.
+//	<prefix>_upstream: one point per upstream channel tagged by the channel
+//	    ID, modulation and lock state, with the frequency, width and signal
+//	    fields.
+//	<prefix>_log: one point per log entry tagged by the event type and
+//	    priority, with the message and a hash of the log string as fields,
+//	    timestamped with the log entry's timestamp. The log entries without
+//	    a timestamp are omitted.
+type Encoder struct {
+	w      io.Writer
+	prefix string
+}
+
+// NewEncoder returns a new encoder which writes to the specified writer.
+func NewEncoder(w io.Writer, opts *Options) *Encoder {
+	e := Encoder{}
+	e.w = w
+	e.prefix = defaultPrefix
+	if opts != nil && opts.Prefix != "" {
+		e.prefix = opts.Prefix
+	}
+	return &e
+}
+
+// Encode writes the points for the specified status. The points other than
+// the log entries are timestamped with the system time reported by the cable
+// modem, or the specified collection time if the system time is unavailable.
+//
+// The log entries with the same timestamp are written with the timestamps
+// offset by a nanosecond for each preceding entry with that timestamp, to
+// avoid overwriting each other. Since the order of the log entries is stable
+// across polls, the same entries retrieved in the subsequent polls overwrite
+// the existing points.
+func (e *Encoder) Encode(st *cablemodemutil.CableModemStatus, collectedAt time.Time) error {
+	ts := st.Connection.SystemTime
+	if ts.IsZero() {
+		ts = collectedAt
+	}
+
+	b := bufio.NewWriter(e.w)
+	e.writeDevice(b, st, ts)
+	for i := range st.Connection.Downstream.Channels {
+		e.writeDownstreamChannel(b, &st.Connection.Downstream.Channels[i], ts)
+	}
+	for i := range st.Connection.Upstream.Channels {
+		e.writeUpstreamChannel(b, &st.Connection.Upstream.Channels[i], ts)
+	}
+	seen := make(map[time.Time]int)
+	for i := range st.Logs {
+		l := &st.Logs[i]
+		if l.Timestamp.IsZero() {
+			continue
+		}
+		e.writeLogEntry(b, l, l.Timestamp.Add(time.Duration(seen[l.Timestamp])))
+		seen[l.Timestamp]++
+	}
+	if err := b.Flush(); err != nil {
+		return fmt.Errorf("unable to write the points, reason: %w", err)
+	}
+	return nil
+}
+
+// Writes the point for the device.
+func (e *Encoder) writeDevice(b *bufio.Writer, st *cablemodemutil.CableModemStatus, ts time.Time) {
+	p := point{
+		measurement: e.prefix + "_" + deviceMeasurement,
+		tags: map[string]string{
+			"model":         st.Info.Model,
+			"serial_number": st.Info.SerialNumber,
+			"mac_address":   st.Info.MACAddress,
+		},
+		timestamp: ts,
+	}
+	p.addInt("uptime_seconds", int64(st.Connection.UpTime/time.Second))
+	p.addBool("internet_connected", st.Connection.InternetConnected)
+	p.addBool("docsis_network_access_allowed", st.Connection.DOCSISNetworkAccessAllowed)
+	if st.Software.FirmwareVersion != "" {
+		p.addString("firmware_version", st.Software.FirmwareVersion)
+	}
+	p.write(b)
+}
+
+// Writes the point for the downstream channel.
+func (e *Encoder) writeDownstreamChannel(b *bufio.Writer, c *cablemodemutil.DownstreamChannelInfo, ts time.Time) {
+	p := point{
+		measurement: e.prefix + "_" + downstreamMeasurement,
+		tags:        channelTags(c.ChannelID, c.Modulation, c.Type, c.Locked),
+		timestamp:   ts,
+	}
+	p.addFloat("frequency_hz", c.FrequencyHZ)
+	p.addFloat("power_dbmv", c.SignalPowerDBMV)
+	p.addFloat("snr_mer_db", c.SignalSNRMERDB)
+	p.addInt("corrected_errors", int64(c.CorrectedErrors))
+	p.addInt("uncorrected_errors", int64(c.UncorrectedErrors))
+	p.write(b)
+}
+
+// Writes the point for the upstream channel.
+func (e *Encoder) writeUpstreamChannel(b *bufio.Writer, c *cablemodemutil.UpstreamChannelInfo, ts time.Time) {
+	p := point{
+		measurement: e.prefix + "_" + upstreamMeasurement,
+		tags:        channelTags(c.ChannelID, c.Modulation, c.Type, c.Locked),
+		timestamp:   ts,
+	}
+	p.addFloat("frequency_hz", c.FrequencyHZ)
+	p.addFloat("width_hz", c.WidthHZ)
+	p.addFloat("power_dbmv", c.SignalPowerDBMV)
+	p.write(b)
+}
+
+// Writes the point for the log entry with the specified timestamp.
+func (e *Encoder) writeLogEntry(b *bufio.Writer, l *cablemodemutil.LogEntry, ts time.Time) {
+	msg := l.Message
+	if msg == "" {
+		msg = l.Log
+	}
+	p := point{
+		measurement: e.prefix + "_" + logMeasurement,
+		tags: map[string]string{
+			"event":    l.Event.String(),
+			"priority": l.Priority.String(),
+		},
+		timestamp: ts,
+	}
+	p.addString("message", msg)
+	p.addInt("severity", int64(l.Severity))
+	p.addString("hash", logHash(l))
+	p.write(b)
+}
+
+// Returns the short hash of the log string in the log entry, used to tell
+// apart the log entries with the same message but different attributes.
+func logHash(l *cablemodemutil.LogEntry) string {
+	h := fnv.New32a()
+	h.Write([]byte(l.Log))
+	return fmt.Sprintf("%08x", h.Sum32())
+}
+
+// Returns the tags for a channel.
+func channelTags(id uint32, modulation string, channelType cablemodemutil.ChannelType, locked bool) map[string]string {
+	return map[string]string{
+		"channel_id":   strconv.FormatUint(uint64(id), 10),
+		"channel_type": channelType.String(),
+		"modulation":   modulation,
+		"locked":       strconv.FormatBool(locked),
+	}
+}
+
+// A point in the line protocol.
+type point struct {
+	measurement string
+	tags        map[string]string
+	// Fields in the order they were added, with the values already encoded.
+	fieldKeys   []string
+	fieldValues []string
+	timestamp   time.Time
+}
+
+// Adds an integer field.
+func (p *point) addInt(key string, val int64) {
+	p.addField(key, strconv.FormatInt(val, 10)+"i")
+}
+
+// Adds a float field without the spurious digits from widening the float32
+// to a float64.
+func (p *point) addFloat(key string, val float32) {
+	p.addField(key, strconv.FormatFloat(float64(val), 'f', -1, 32))
+}
+
+// Adds a boolean field.
+func (p *point) addBool(key string, val bool) {
+	p.addField(key, strconv.FormatBool(val))
+}
+
+// Adds a string field.
+func (p *point) addString(key string, val string) {
+	p.addField(key, "\""+stringFieldEscaper.Replace(val)+"\"")
+}
+
+// Adds a field with the specified encoded value.
+func (p *point) addField(key string, val string) {
+	p.fieldKeys = append(p.fieldKeys, key)
+	p.fieldValues = append(p.fieldValues, val)
+}
+
+// Writes the point as a line. Tags with empty values are omitted since they
+// are not allowed in the line protocol, and the tags are sorted by the key
+// as recommended for performance.
+func (p *point) write(b *bufio.Writer) {
+	b.WriteString(measurementEscaper.Replace(p.measurement))
+
+	keys := make([]string, 0, len(p.tags))
+	for k, v := range p.tags {
+		if v != "" {
+			keys = append(keys, k)
+		}
+	}
+	sort.Strings(keys)
+	for _, k := range keys {
+		b.WriteString("," + keyEscaper.Replace(k) + "=" + keyEscaper.Replace(p.tags[k]))
+	}
+
+	for i, k := range p.fieldKeys {
+		if i == 0 {
+			b.WriteByte(' ')
+		} else {
+			b.WriteByte(',')
+		}
+		b.WriteString(keyEscaper.Replace(k) + "=" + p.fieldValues[i])
+	}
+	b.WriteString(" " + strconv.FormatInt(p.timestamp.UnixNano(), 10) + "\n")
+}
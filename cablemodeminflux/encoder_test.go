@@ -0,0 +1,140 @@
+package cablemodeminflux
+
+import (
+	"bytes"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/tuxdude/cablemodemutil"
+)
+
+func TestEncoderEncode(t *testing.T) {
+	systemTime := time.Date(2021, 10, 23, 10, 12, 47, 0, time.UTC)
+	logTime := time.Date(2021, 10, 22, 10, 5, 43, 0, time.UTC)
+	st := cablemodemutil.CableModemStatus{
+		Info: cablemodemutil.DeviceInfo{
+			Model:        "S33",
+			SerialNumber: "1234567890ABCDEF",
+			MACAddress:   "AA:BB:CC:DD:EE:FF",
+		},
+		Software: cablemodemutil.SoftwareStatus{
+			FirmwareVersion: "TB01.03.001.10_012022_212.S3",
+		},
+		Connection: cablemodemutil.ConnectionStatus{
+			SystemTime:                 systemTime,
+			UpTime:                     3*time.Hour + 30*time.Second,
+			DOCSISNetworkAccessAllowed: true,
+			InternetConnected:          true,
+			Downstream: cablemodemutil.DownstreamConnectionStatus{
+				Channels: []cablemodemutil.DownstreamChannelInfo{
+					{
+						Locked:            true,
+						Type:              cablemodemutil.ChannelTypeSCQAM,
+						Modulation:        "QAM256",
+						ChannelID:         20,
+						FrequencyHZ:       723000000,
+						SignalPowerDBMV:   4.3,
+						SignalSNRMERDB:    40.9,
+						CorrectedErrors:   12,
+						UncorrectedErrors: 0,
+					},
+				},
+			},
+			Upstream: cablemodemutil.UpstreamConnectionStatus{
+				Channels: []cablemodemutil.UpstreamChannelInfo{
+					{
+						Locked:          false,
+						Type:            cablemodemutil.ChannelTypeOFDMA,
+						Modulation:      "OFDMA",
+						ChannelID:       5,
+						WidthHZ:         44400000,
+						FrequencyHZ:     37000000,
+						SignalPowerDBMV: 41,
+					},
+				},
+			},
+		},
+		Logs: []cablemodemutil.LogEntry{
+			{
+				Timestamp: logTime,
+				Log:       `Login Failed from 192.168.0.23 "admin"`,
+				Severity:  0,
+				Priority:  cablemodemutil.LogPriorityInformational,
+				Event:     cablemodemutil.LogEventLoginFailure,
+			},
+		},
+	}
+
+	var buf bytes.Buffer
+	err := NewEncoder(&buf, nil).Encode(&st, time.Now())
+	if err != nil {
+		t.Fatalf("Encode() failed, reason: %s", err)
+	}
+
+	want := strings.Join([]string{
+		`cablemodem_device,mac_address=AA:BB:CC:DD:EE:FF,model=S33,serial_number=1234567890ABCDEF ` +
+			`uptime_seconds=10830i,internet_connected=true,docsis_network_access_allowed=true,` +
+			`firmware_version="TB01.03.001.10_012022_212.S3" 1634983967000000000`,
+		`cablemodem_downstream,channel_id=20,channel_type=SC-QAM,locked=true,modulation=QAM256 ` +
+			`frequency_hz=723000000,power_dbmv=4.3,snr_mer_db=40.9,corrected_errors=12i,uncorrected_errors=0i ` +
+			`1634983967000000000`,
+		`cablemodem_upstream,channel_id=5,channel_type=OFDMA,locked=false,modulation=OFDMA ` +
+			`frequency_hz=37000000,width_hz=44400000,power_dbmv=41 1634983967000000000`,
+		`cablemodem_log,event=LOGIN_FAILURE,priority=Informational ` +
+			`message="Login Failed from 192.168.0.23 \"admin\"",severity=0i,hash="f69bd95c" 1634897143000000000`,
+		``,
+	}, "\n")
+	if got := buf.String(); got != want {
+		t.Errorf("Encode() output mismatch\ngot:\n%s\nwant:\n%s", got, want)
+	}
+}
+
+func TestEncoderEncodeCollectionTime(t *testing.T) {
+	collectedAt := time.Date(2022, 1, 2, 3, 4, 5, 0, time.UTC)
+	st := cablemodemutil.CableModemStatus{}
+	st.Info.Model = "Model With Spaces,Commas"
+
+	var buf bytes.Buffer
+	err := NewEncoder(&buf, &Options{Prefix: "cm"}).Encode(&st, collectedAt)
+	if err != nil {
+		t.Fatalf("Encode() failed, reason: %s", err)
+	}
+
+	want := `cm_device,model=Model\ With\ Spaces\,Commas ` +
+		`uptime_seconds=0i,internet_connected=false,docsis_network_access_allowed=false 1641092645000000000` + "\n"
+	if got := buf.String(); got != want {
+		t.Errorf("Encode() output = %q want: %q", got, want)
+	}
+}
+
+func TestEncoderEncodeLogs(t *testing.T) {
+	logTime := time.Date(2021, 10, 22, 10, 5, 43, 0, time.UTC)
+	st := cablemodemutil.CableModemStatus{}
+	st.Info.Model = "S33"
+	st.Logs = []cablemodemutil.LogEntry{
+		{Timestamp: logTime, Log: "T3 time-out"},
+		{Timestamp: logTime, Log: "T4 time-out"},
+		{Timestamp: logTime, Log: "T3 time-out"},
+		{Log: "No Ranging Response received - T3 time-out"},
+	}
+
+	var buf bytes.Buffer
+	err := NewEncoder(&buf, nil).Encode(&st, logTime.Add(time.Hour))
+	if err != nil {
+		t.Fatalf("Encode() failed, reason: %s", err)
+	}
+
+	// The entries with the same timestamp must not overwrite each other,
+	// and the entries without a timestamp are omitted.
+	lines := strings.Split(strings.TrimSpace(buf.String()), "\n")[1:]
+	want := []string{
+		`cablemodem_log,event=UNKNOWN,priority=Unknown message="T3 time-out",severity=0i,hash="1571ea90" 1634897143000000000`,
+		`cablemodem_log,event=UNKNOWN,priority=Unknown message="T4 time-out",severity=0i,hash="9daeae03" 1634897143000000001`,
+		`cablemodem_log,event=UNKNOWN,priority=Unknown message="T3 time-out",severity=0i,hash="1571ea90" 1634897143000000002`,
+	}
+	if !reflect.DeepEqual(lines, want) {
+		t.Errorf("Encode() log points = %q want: %q", lines, want)
+	}
+}
@@ -0,0 +1,190 @@
+// Package cablemodemprom exposes the cable modem status as Prometheus metrics
+// in the OpenMetrics text format, without depending on a Prometheus client
+// library.
+//
+// The Exporter is an http.Handler which retrieves the status from the cable
+// modem on each scrape:
+//
+//	cm := cablemodemutil.NewStatusRetriever(&input)
+//	http.Handle("/metrics", cablemodemprom.New(cm, nil))
+package cablemodemprom
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/tuxdude/cablemodemutil"
+)
+
+const (
+	defaultNamespace = "cablemodem"
+	defaultTimeout   = 30 * time.Second
+	contentType      = "application/openmetrics-text; version=1.0.0; charset=utf-8"
+)
+
+// StatusRetriever is used to retrieve the cable modem status on each scrape.
+// *cablemodemutil.Retriever implements this interface.
+type StatusRetriever interface {
+	StatusContext(ctx context.Context) (*cablemodemutil.CableModemStatus, error)
+}
+
+// Options is used to specify the options of the Exporter.
+type Options struct {
+	// Prefix for all the metric names, defaults to "cablemodem".
+	Namespace string
+	// Timeout for retrieving the status on each scrape, defaults to 30
+	// seconds.
+	Timeout time.Duration
+}
+
+// Exporter exposes the cable modem status as metrics.
+type Exporter struct {
+	retriever StatusRetriever
+	namespace string
+	timeout   time.Duration
+	// Serializes the scrapes to avoid overwhelming the cable modem.
+	mu sync.Mutex
+}
+
+// New returns a new exporter which retrieves the status using the specified
+// retriever.
+func New(r StatusRetriever, opts *Options) *Exporter {
+	e := Exporter{}
+	e.retriever = r
+	e.namespace = defaultNamespace
+	e.timeout = defaultTimeout
+	if opts != nil {
+		if opts.Namespace != "" {
+			e.namespace = opts.Namespace
+		}
+		if opts.Timeout > 0 {
+			e.timeout = opts.Timeout
+		}
+	}
+	return &e
+}
+
+// ServeHTTP retrieves the status from the cable modem and serves the metrics.
+func (e *Exporter) ServeHTTP(w http.ResponseWriter, req *http.Request) {
+	var buf bytes.Buffer
+	e.WriteMetrics(req.Context(), &buf)
+	w.Header().Set("Content-Type", contentType)
+	_, _ = w.Write(buf.Bytes())
+}
+
+// WriteMetrics retrieves the status from the cable modem and writes the
+// metrics in the OpenMetrics text format to the specified writer. Failures in
+// retrieving the status are reported using the scrape success metric.
+func (e *Exporter) WriteMetrics(ctx context.Context, w io.Writer) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	ctx, cancel := context.WithTimeout(ctx, e.timeout)
+	defer cancel()
+	start := time.Now()
+	st, err := e.retriever.StatusContext(ctx)
+	duration := time.Since(start)
+
+	m := newMetricWriter(w, e.namespace)
+	m.gauge("scrape_duration_seconds", "Duration of retrieving the status from the cable modem.",
+		sample(nil, duration.Seconds()))
+	if err != nil {
+		m.gauge("scrape_success", "Whether retrieving the status from the cable modem succeeded.",
+			sample(nil, 0))
+		m.eof()
+		return
+	}
+	m.gauge("scrape_success", "Whether retrieving the status from the cable modem succeeded.",
+		sample(nil, 1))
+	writeStatusMetrics(m, st)
+	m.eof()
+}
+
+// Writes the metrics for the specified status.
+// nolint:funlen
+func writeStatusMetrics(m *metricWriter, st *cablemodemutil.CableModemStatus) {
+	m.info("device", "Cable modem device information.", sample(labels{
+		"model":         st.Info.Model,
+		"serial_number": st.Info.SerialNumber,
+		"mac_address":   st.Info.MACAddress,
+	}, 1))
+	m.info("firmware", "Cable modem firmware information.", sample(labels{
+		"firmware_version": st.Software.FirmwareVersion,
+		"customer_version": st.Software.CustomerVersion,
+		"hd_version":       st.Software.HDVersion,
+		"docsis_version":   st.Software.DOCSISSpecVersion,
+	}, 1))
+	m.gauge("uptime_seconds", "Duration for which the connection has been up.",
+		sample(nil, st.Connection.UpTime.Seconds()))
+	m.gauge("internet_connected", "Whether the internet connection is up.",
+		sample(nil, boolValue(st.Connection.InternetConnected)))
+	m.gauge("docsis_network_access_allowed", "Whether DOCSIS network access is allowed.",
+		sample(nil, boolValue(st.Connection.DOCSISNetworkAccessAllowed)))
+
+	ds := st.Connection.Downstream.Channels
+	dsSamples := func(value func(c *cablemodemutil.DownstreamChannelInfo) float64) []metricSample {
+		res := make([]metricSample, len(ds))
+		for i := range ds {
+			res[i] = sample(channelLabels(ds[i].ChannelID, ds[i].Modulation, ds[i].Type), value(&ds[i]))
+		}
+		return res
+	}
+	m.gauge("downstream_locked", "Whether the downstream channel is locked.",
+		dsSamples(func(c *cablemodemutil.DownstreamChannelInfo) float64 { return boolValue(c.Locked) })...)
+	m.gauge("downstream_frequency_hertz", "Frequency of the downstream channel.",
+		dsSamples(func(c *cablemodemutil.DownstreamChannelInfo) float64 { return float32Value(c.FrequencyHZ) })...)
+	m.gauge("downstream_power_dbmv", "Signal power of the downstream channel in dBmV.",
+		dsSamples(func(c *cablemodemutil.DownstreamChannelInfo) float64 { return float32Value(c.SignalPowerDBMV) })...)
+	m.gauge("downstream_snr_db", "Signal SNR/MER of the downstream channel in dB.",
+		dsSamples(func(c *cablemodemutil.DownstreamChannelInfo) float64 { return float32Value(c.SignalSNRMERDB) })...)
+	m.counter("downstream_corrected_errors", "Corrected errors on the downstream channel.",
+		dsSamples(func(c *cablemodemutil.DownstreamChannelInfo) float64 { return float64(c.CorrectedErrors) })...)
+	m.counter("downstream_uncorrected_errors", "Uncorrected errors on the downstream channel.",
+		dsSamples(func(c *cablemodemutil.DownstreamChannelInfo) float64 { return float64(c.UncorrectedErrors) })...)
+
+	us := st.Connection.Upstream.Channels
+	usSamples := func(value func(c *cablemodemutil.UpstreamChannelInfo) float64) []metricSample {
+		res := make([]metricSample, len(us))
+		for i := range us {
+			res[i] = sample(channelLabels(us[i].ChannelID, us[i].Modulation, us[i].Type), value(&us[i]))
+		}
+		return res
+	}
+	m.gauge("upstream_locked", "Whether the upstream channel is locked.",
+		usSamples(func(c *cablemodemutil.UpstreamChannelInfo) float64 { return boolValue(c.Locked) })...)
+	m.gauge("upstream_frequency_hertz", "Frequency of the upstream channel.",
+		usSamples(func(c *cablemodemutil.UpstreamChannelInfo) float64 { return float32Value(c.FrequencyHZ) })...)
+	m.gauge("upstream_width_hertz", "Width of the upstream channel.",
+		usSamples(func(c *cablemodemutil.UpstreamChannelInfo) float64 { return float32Value(c.WidthHZ) })...)
+	m.gauge("upstream_power_dbmv", "Signal power of the upstream channel in dBmV.",
+		usSamples(func(c *cablemodemutil.UpstreamChannelInfo) float64 { return float32Value(c.SignalPowerDBMV) })...)
+}
+
+// Returns the labels for a channel.
+func channelLabels(id uint32, modulation string, channelType cablemodemutil.ChannelType) labels {
+	return labels{
+		"channel_id":   strconv.FormatUint(uint64(id), 10),
+		"modulation":   modulation,
+		"channel_type": channelType.String(),
+	}
+}
+
+// Returns the metric value for the specified float32 without the spurious
+// digits from widening it to a float64 (Eg. 4.7 instead of 4.699999809).
+func float32Value(f float32) float64 {
+	v, _ := strconv.ParseFloat(strconv.FormatFloat(float64(f), 'g', -1, 32), 64)
+	return v
+}
+
+// Returns the metric value for the specified bool.
+func boolValue(b bool) float64 {
+	if b {
+		return 1
+	}
+	return 0
+}
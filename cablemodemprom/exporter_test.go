@@ -0,0 +1,120 @@
+package cablemodemprom
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/tuxdude/cablemodemutil"
+	"github.com/tuxdude/cablemodemutil/cablemodemsim"
+)
+
+// A retriever which always fails.
+type failingRetriever struct{}
+
+func (failingRetriever) StatusContext(ctx context.Context) (*cablemodemutil.CableModemStatus, error) {
+	return nil, errors.New("connection refused")
+}
+
+// Starts a simulated cable modem and returns an exporter retrieving the
+// status from it.
+func newTestExporter(t *testing.T) *Exporter {
+	t.Helper()
+	sim := cablemodemsim.New(&cablemodemsim.Config{
+		Username: "admin",
+		Password: "password",
+	})
+	srv := httptest.NewTLSServer(sim)
+	t.Cleanup(srv.Close)
+
+	r := cablemodemutil.NewStatusRetriever(&cablemodemutil.RetrieverInput{
+		Host:          strings.TrimPrefix(srv.URL, "https://"),
+		Protocol:      "https",
+		HTTPClient:    srv.Client(),
+		Username:      "admin",
+		ClearPassword: "password",
+	})
+	return New(r, nil)
+}
+
+func TestExporterServeHTTP(t *testing.T) {
+	e := newTestExporter(t)
+	srv := httptest.NewServer(e)
+	defer srv.Close()
+
+	resp, err := http.Get(srv.URL)
+	if err != nil {
+		t.Fatalf("GET failed, reason: %s", err)
+	}
+	defer resp.Body.Close()
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		t.Fatalf("unable to read the response body, reason: %s", err)
+	}
+	if got := resp.Header.Get("Content-Type"); got != contentType {
+		t.Errorf("Content-Type = %q want: %q", got, contentType)
+	}
+
+	got := string(body)
+	want := []string{
+		"# TYPE cablemodem_scrape_success gauge\n",
+		"cablemodem_scrape_success 1\n",
+		"# TYPE cablemodem_firmware info\n",
+		`cablemodem_firmware_info{customer_version="`,
+		"cablemodem_uptime_seconds 310533\n",
+		"cablemodem_internet_connected 1\n",
+		`cablemodem_downstream_power_dbmv{channel_id="20",channel_type="SC-QAM",modulation="QAM256"} 4.3` + "\n",
+		`cablemodem_downstream_snr_db{channel_id="20",channel_type="SC-QAM",modulation="QAM256"} 40.9` + "\n",
+		"# TYPE cablemodem_downstream_corrected_errors counter\n",
+		`cablemodem_downstream_corrected_errors_total{channel_id="33",channel_type="OFDM",modulation="OFDM PLC"} 1234` + "\n",
+		`cablemodem_downstream_uncorrected_errors_total{channel_id="2",channel_type="SC-QAM",modulation="QAM256"} 1` + "\n",
+		`cablemodem_downstream_frequency_hertz{channel_id="1",channel_type="SC-QAM",modulation="QAM256"} 5.85e+08` + "\n",
+		`cablemodem_upstream_locked{channel_id="5",channel_type="OFDMA",modulation="OFDMA"} 1` + "\n",
+		`cablemodem_upstream_power_dbmv{channel_id="2",channel_type="SC-QAM",modulation="SC-QAM"} 44.5` + "\n",
+	}
+	for _, w := range want {
+		if !strings.Contains(got, w) {
+			t.Errorf("metrics missing %q, got:\n%s", w, got)
+		}
+	}
+	if !strings.HasSuffix(got, "# EOF\n") {
+		t.Errorf("metrics not terminated with the EOF marker, got:\n%s", got)
+	}
+}
+
+func TestExporterScrapeFailure(t *testing.T) {
+	e := New(failingRetriever{}, &Options{Namespace: "cm"})
+
+	var buf bytes.Buffer
+	e.WriteMetrics(context.Background(), &buf)
+	got := buf.String()
+	if !strings.Contains(got, "cm_scrape_success 0\n") {
+		t.Errorf("metrics missing the failed scrape, got:\n%s", got)
+	}
+	if strings.Contains(got, "cm_uptime_seconds") {
+		t.Errorf("metrics contain the status for a failed scrape, got:\n%s", got)
+	}
+	if !strings.HasSuffix(got, "# EOF\n") {
+		t.Errorf("metrics not terminated with the EOF marker, got:\n%s", got)
+	}
+}
+
+func TestMetricWriterEscaping(t *testing.T) {
+	var buf bytes.Buffer
+	m := newMetricWriter(&buf, "cm")
+	m.gauge("test", "Help with \\ and\nnewline.", sample(labels{"b": "x\"y", "a": "1\\2\n"}, 0.5))
+	m.eof()
+
+	want := "# TYPE cm_test gauge\n" +
+		"# HELP cm_test Help with \\\\ and\\nnewline.\n" +
+		"cm_test{a=\"1\\\\2\\n\",b=\"x\\\"y\"} 0.5\n" +
+		"# EOF\n"
+	if got := buf.String(); got != want {
+		t.Errorf("metricWriter output = %q want: %q", got, want)
+	}
+}
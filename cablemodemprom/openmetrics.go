@@ -0,0 +1,120 @@
+package cablemodemprom
+
+import (
+	"bufio"
+	"io"
+	"math"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// Types of the metric families.
+const (
+	metricTypeGauge   = "gauge"
+	metricTypeCounter = "counter"
+	metricTypeInfo    = "info"
+)
+
+// Escapes the label values and help texts.
+// nolint:gochecknoglobals
+var escaper = strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`)
+
+// Labels for a metric sample keyed by the label name.
+type labels map[string]string
+
+// A sample within a metric family.
+type metricSample struct {
+	labels labels
+	value  float64
+}
+
+// Returns a sample with the specified labels and value.
+func sample(l labels, value float64) metricSample {
+	return metricSample{labels: l, value: value}
+}
+
+// Writes the metric families in the OpenMetrics text format.
+type metricWriter struct {
+	w         *bufio.Writer
+	namespace string
+}
+
+// Returns a new metric writer which prefixes the metric names with the
+// specified namespace.
+func newMetricWriter(w io.Writer, namespace string) *metricWriter {
+	return &metricWriter{w: bufio.NewWriter(w), namespace: namespace}
+}
+
+// Writes a gauge metric family.
+func (m *metricWriter) gauge(name string, help string, samples ...metricSample) {
+	m.family(name, metricTypeGauge, help, "", samples)
+}
+
+// Writes a counter metric family, whose samples are suffixed with "_total".
+func (m *metricWriter) counter(name string, help string, samples ...metricSample) {
+	m.family(name, metricTypeCounter, help, "_total", samples)
+}
+
+// Writes an info metric family, whose samples are suffixed with "_info".
+func (m *metricWriter) info(name string, help string, samples ...metricSample) {
+	m.family(name, metricTypeInfo, help, "_info", samples)
+}
+
+// Writes the terminating EOF marker and flushes the output.
+func (m *metricWriter) eof() {
+	m.w.WriteString("# EOF\n")
+	m.w.Flush()
+}
+
+// Writes the metric family with the specified samples.
+func (m *metricWriter) family(name string, metricType string, help string, suffix string, samples []metricSample) {
+	name = m.namespace + "_" + name
+	m.w.WriteString("# TYPE " + name + " " + metricType + "\n")
+	m.w.WriteString("# HELP " + name + " " + escape(help) + "\n")
+	for _, s := range samples {
+		m.w.WriteString(name + suffix)
+		m.writeLabels(s.labels)
+		m.w.WriteString(" " + formatValue(s.value) + "\n")
+	}
+}
+
+// Writes the specified labels sorted by the label name.
+func (m *metricWriter) writeLabels(l labels) {
+	if len(l) == 0 {
+		return
+	}
+	names := make([]string, 0, len(l))
+	for name := range l {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	m.w.WriteByte('{')
+	for i, name := range names {
+		if i > 0 {
+			m.w.WriteByte(',')
+		}
+		m.w.WriteString(name + "=\"" + escape(l[name]) + "\"")
+	}
+	m.w.WriteByte('}')
+}
+
+// Returns the OpenMetrics text representation of the specified value.
+func formatValue(v float64) string {
+	switch {
+	case math.IsNaN(v):
+		return "NaN"
+	case math.IsInf(v, 1):
+		return "+Inf"
+	case math.IsInf(v, -1):
+		return "-Inf"
+	}
+	return strconv.FormatFloat(v, 'g', -1, 64)
+}
+
+// Escapes the backslashes, double quotes and line feeds in the specified
+// label value or help text.
+func escape(v string) string {
+	return escaper.Replace(v)
+}
@@ -0,0 +1,24 @@
+package cablemodemsim
+
+import (
+	"crypto/hmac"
+	"crypto/md5" // nolint:gosec
+	"crypto/sha256"
+	"fmt"
+)
+
+// Generates HMAC-MD5 using the specified key and message strings, the same
+// way as the cable modem.
+func genHMACMD5(key string, msg string) string {
+	h := hmac.New(md5.New, []byte(key))
+	h.Write([]byte(msg))
+	return fmt.Sprintf("%X", h.Sum(nil))
+}
+
+// Generates HMAC-SHA256 using the specified key and message strings, the
+// same way as the cable modem with the newer firmware.
+func genHMACSHA256(key string, msg string) string {
+	h := hmac.New(sha256.New, []byte(key))
+	h.Write([]byte(msg))
+	return fmt.Sprintf("%X", h.Sum(nil))
+}
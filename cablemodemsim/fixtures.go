@@ -0,0 +1,42 @@
+package cablemodemsim
+
+import (
+	_ "embed" // Needed for embedding the fixtures.
+	"encoding/json"
+	"fmt"
+	"strings"
+)
+
+//go:embed fixtures/s33.json
+var s33Fixture []byte // nolint:gochecknoglobals
+
+// DefaultResponses returns the unpacked responses for the status actions of
+// an Arris S33 cable modem, keyed by the action.
+func DefaultResponses() map[string]map[string]interface{} {
+	res, err := ParseResponses(s33Fixture)
+	if err != nil {
+		panic(fmt.Sprintf("invalid embedded fixture, reason: %s", err))
+	}
+	return res
+}
+
+// ParseResponses parses the specified JSON encoded GetMultipleHNAPs unpacked
+// response (i.e. the raw status) into responses keyed by the action, which
+// can be used in the Config. Entries which are not JSON objects are skipped.
+func ParseResponses(data []byte) (map[string]map[string]interface{}, error) {
+	var raw map[string]interface{}
+	err := json.Unmarshal(data, &raw)
+	if err != nil {
+		return nil, fmt.Errorf("failed to decode responses, reason: %w", err)
+	}
+
+	res := make(map[string]map[string]interface{})
+	for key, val := range raw {
+		resp, ok := val.(map[string]interface{})
+		if !ok || !strings.HasSuffix(key, "Response") {
+			continue
+		}
+		res[strings.TrimSuffix(key, "Response")] = resp
+	}
+	return res, nil
+}
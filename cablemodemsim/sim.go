@@ -0,0 +1,417 @@
+// Package cablemodemsim provides a local simulator of an HNAP1 cable modem
+// (modeled after the Arris S33) for use in tests and demos.
+//
+// The Simulator is an http.Handler and is meant to be served using
+// net/http/httptest:
+//
+//	sim := cablemodemsim.New(&cablemodemsim.Config{Username: "admin", Password: "password"})
+//	srv := httptest.NewTLSServer(sim)
+//	defer srv.Close()
+package cablemodemsim
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	soapNamespace   = "http://purenetworks.com/HNAP1"
+	hnapPath        = "/HNAP1/"
+	loginAction     = "Login"
+	queryAction     = "GetMultipleHNAPs"
+	actionHeader    = "SOAPAction"
+	hnapAuthHeader  = "HNAP_AUTH"
+	withoutLoginKey = "withoutLoginKey"
+	resultOK        = "OK"
+	resultFailed    = "FAILED"
+	resultError     = "ERROR"
+
+	// Action served without logging in, describing the device.
+	deviceSettingsAction = "GetDeviceSettings"
+)
+
+// Config is used to specify the configuration of the Simulator.
+type Config struct {
+	// User name accepted by the simulator.
+	Username string
+	// Password accepted by the simulator.
+	Password string
+	// Unpacked responses for the actions served by the simulator (either
+	// directly or within GetMultipleHNAPs), keyed by the action. The result
+	// key for the action is added automatically if unspecified. Defaults to
+	// DefaultResponses() if nil.
+	Responses map[string]map[string]interface{}
+	// Duration of inactivity after which a session expires, and further
+	// requests within the session fail with a 404 status code like the real
+	// cable modem. Sessions never expire if unspecified.
+	SessionTimeout time.Duration
+	// If true, uses HMAC-SHA256 instead of HMAC-MD5 for the login
+	// handshake and the HNAP auth like the newer firmware. Requests using
+	// HMAC-MD5 are rejected.
+	UseSHA256 bool
+}
+
+// Faults is used to specify the faults injected by the Simulator.
+type Faults struct {
+	// If true, responds with a malformed JSON payload to all the
+	// authenticated actions.
+	MalformedJSON bool
+	// Delay before responding to every request. The delay is cut short if
+	// the client cancels the request.
+	Delay time.Duration
+	// If non-empty, responds with this result instead of "OK" to all the
+	// authenticated actions.
+	Result string
+}
+
+// Call contains an authenticated action request received by the Simulator.
+type Call struct {
+	// The SOAP action.
+	Action string
+	// The payload in the request for the action.
+	Payload map[string]interface{}
+}
+
+// Simulator simulates an HNAP1 cable modem.
+type Simulator struct {
+	cfg      Config
+	faults   Faults
+	sessions map[string]*session
+	logins   int
+	calls    []Call
+	mu       sync.Mutex
+}
+
+// An authentication session with the simulator.
+type session struct {
+	privateKey    string
+	challenge     string
+	authenticated bool
+	lastUsed      time.Time
+}
+
+// New returns a new simulator with the specified configuration. The
+// responses in the configuration are copied, and hence are not modified by
+// SetResponse.
+func New(cfg *Config) *Simulator {
+	s := Simulator{}
+	s.cfg = *cfg
+	if s.cfg.Responses == nil {
+		s.cfg.Responses = DefaultResponses()
+	} else {
+		s.cfg.Responses = make(map[string]map[string]interface{}, len(cfg.Responses))
+		for action, resp := range cfg.Responses {
+			s.cfg.Responses[action] = make(map[string]interface{}, len(resp))
+			for k, v := range resp {
+				s.cfg.Responses[action][k] = v
+			}
+		}
+	}
+	s.sessions = make(map[string]*session)
+	return &s
+}
+
+// SetFaults sets the faults to be injected by the simulator, replacing the
+// previously set faults.
+func (s *Simulator) SetFaults(faults Faults) {
+	s.mu.Lock()
+	s.faults = faults
+	s.mu.Unlock()
+}
+
+// ExpireSessions expires all the current sessions, causing further requests
+// within those sessions to fail with a 404 status code.
+func (s *Simulator) ExpireSessions() {
+	s.mu.Lock()
+	s.sessions = make(map[string]*session)
+	s.mu.Unlock()
+}
+
+// SetResponse sets the unpacked response for the specified action.
+func (s *Simulator) SetResponse(action string, resp map[string]interface{}) {
+	s.mu.Lock()
+	s.cfg.Responses[action] = resp
+	s.mu.Unlock()
+}
+
+// Logins returns the number of successful logins so far.
+func (s *Simulator) Logins() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.logins
+}
+
+// Calls returns the authenticated action requests received so far, excluding
+// GetMultipleHNAPs requests.
+func (s *Simulator) Calls() []Call {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	res := make([]Call, len(s.calls))
+	copy(res, s.calls)
+	return res
+}
+
+// ServeHTTP serves the HNAP1 requests.
+func (s *Simulator) ServeHTTP(w http.ResponseWriter, req *http.Request) {
+	if req.URL.Path != hnapPath || req.Method != http.MethodPost {
+		http.NotFound(w, req)
+		return
+	}
+
+	action, err := parseActionHeader(req.Header.Get(actionHeader))
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	var body map[string]map[string]interface{}
+	err = json.NewDecoder(req.Body).Decode(&body)
+	if err != nil {
+		http.Error(w, fmt.Sprintf("invalid request payload: %s", err), http.StatusBadRequest)
+		return
+	}
+	payload, ok := body[action]
+	if !ok {
+		http.Error(w, fmt.Sprintf("payload for action %q not found", action), http.StatusBadRequest)
+		return
+	}
+
+	s.mu.Lock()
+	faults := s.faults
+	s.mu.Unlock()
+	if faults.Delay > 0 {
+		// The request body has been consumed by now, allowing the server to
+		// detect the client closing the connection.
+		select {
+		case <-time.After(faults.Delay):
+		case <-req.Context().Done():
+			return
+		}
+	}
+
+	switch action {
+	case loginAction:
+		s.serveLogin(w, req, payload)
+	case deviceSettingsAction:
+		s.serveDeviceSettings(w, req)
+	default:
+		s.serveAction(w, req, action, payload, &faults)
+	}
+}
+
+// Serves the login request and login actions.
+func (s *Simulator) serveLogin(w http.ResponseWriter, req *http.Request, payload map[string]interface{}) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	switch payload["Action"] {
+	case "request":
+		if !s.validHNAPAuth(req, withoutLoginKey, loginAction) {
+			http.Error(w, "invalid HNAP auth", http.StatusUnauthorized)
+			return
+		}
+		if payload["Username"] != s.cfg.Username {
+			writeResponse(w, loginAction, map[string]interface{}{}, resultFailed)
+			return
+		}
+		uid := randomHex(8)
+		publicKey := randomHex(10)
+		challenge := randomHex(10)
+		s.sessions[uid] = &session{
+			privateKey: s.genHMAC(publicKey+s.cfg.Password, challenge),
+			challenge:  challenge,
+			lastUsed:   time.Now(),
+		}
+		writeResponse(w, loginAction, map[string]interface{}{
+			"Cookie":    uid,
+			"PublicKey": publicKey,
+			"Challenge": challenge,
+		}, resultOK)
+	case "login":
+		uid, sess := s.sessionFromCookies(req)
+		if sess == nil {
+			http.Error(w, "invalid session", http.StatusUnauthorized)
+			return
+		}
+		// A wrong password results in both the HNAP auth and the login
+		// password being computed using a wrong private key.
+		if !s.validHNAPAuth(req, sess.privateKey, loginAction) ||
+			payload["LoginPassword"] != s.genHMAC(sess.privateKey, sess.challenge) {
+			delete(s.sessions, uid)
+			writeResponse(w, loginAction, map[string]interface{}{}, resultFailed)
+			return
+		}
+		sess.authenticated = true
+		sess.lastUsed = time.Now()
+		s.logins++
+		writeResponse(w, loginAction, map[string]interface{}{}, resultOK)
+	default:
+		http.Error(w, fmt.Sprintf("invalid login action %q", payload["Action"]), http.StatusBadRequest)
+	}
+}
+
+// Serves the device settings, which do not require logging in.
+func (s *Simulator) serveDeviceSettings(w http.ResponseWriter, req *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if !s.validHNAPAuth(req, withoutLoginKey, deviceSettingsAction) {
+		http.Error(w, "invalid HNAP auth", http.StatusUnauthorized)
+		return
+	}
+	resp, ok := s.cfg.Responses[deviceSettingsAction]
+	if !ok {
+		writeResponse(w, deviceSettingsAction, map[string]interface{}{}, resultError)
+		return
+	}
+	writeResponse(w, deviceSettingsAction, resp, resultOK)
+}
+
+// Serves the authenticated actions.
+func (s *Simulator) serveAction(
+	w http.ResponseWriter,
+	req *http.Request,
+	action string,
+	payload map[string]interface{},
+	faults *Faults,
+) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	_, sess := s.sessionFromCookies(req)
+	if sess == nil || !sess.authenticated || !s.validHNAPAuth(req, sess.privateKey, action) {
+		// The real cable modem responds with a 404 for requests with
+		// expired or invalid credentials.
+		http.NotFound(w, req)
+		return
+	}
+	sess.lastUsed = time.Now()
+
+	if faults.MalformedJSON {
+		w.Header().Set("Content-Type", "application/json")
+		fmt.Fprintf(w, "{\"%sResponse\": {", action)
+		return
+	}
+	result := resultOK
+	if faults.Result != "" {
+		result = faults.Result
+	}
+
+	if action != queryAction {
+		s.calls = append(s.calls, Call{Action: action, Payload: payload})
+		resp, ok := s.cfg.Responses[action]
+		switch {
+		case ok:
+			writeResponse(w, action, resp, result)
+		case strings.HasPrefix(action, "Set"):
+			writeResponse(w, action, map[string]interface{}{}, result)
+		default:
+			writeResponse(w, action, map[string]interface{}{}, resultError)
+		}
+		return
+	}
+
+	unpacked := make(map[string]interface{})
+	for subAction := range payload {
+		resp, ok := s.cfg.Responses[subAction]
+		if !ok {
+			unpacked[subAction+"Response"] = withResult(subAction, map[string]interface{}{}, resultError)
+			continue
+		}
+		unpacked[subAction+"Response"] = withResult(subAction, resp, resultOK)
+	}
+	writeResponse(w, action, unpacked, result)
+}
+
+// Returns the session corresponding to the cookies in the request if the
+// private key in the cookies matches, nil otherwise.
+func (s *Simulator) sessionFromCookies(req *http.Request) (string, *session) {
+	uid, err := req.Cookie("uid")
+	if err != nil {
+		return "", nil
+	}
+	sess, ok := s.sessions[uid.Value]
+	if !ok {
+		return "", nil
+	}
+	if s.cfg.SessionTimeout > 0 && time.Since(sess.lastUsed) > s.cfg.SessionTimeout {
+		delete(s.sessions, uid.Value)
+		return "", nil
+	}
+	if sess.authenticated {
+		key, err := req.Cookie("PrivateKey")
+		if err != nil || key.Value != sess.privateKey {
+			return "", nil
+		}
+	}
+	return uid.Value, sess
+}
+
+// Parses the action from the SOAP action header value.
+func parseActionHeader(header string) (string, error) {
+	uri := strings.Trim(header, "\"")
+	prefix := soapNamespace + "/"
+	if !strings.HasPrefix(uri, prefix) {
+		return "", fmt.Errorf("invalid SOAP action header %q", header)
+	}
+	return strings.TrimPrefix(uri, prefix), nil
+}
+
+// Returns true if the HNAP auth header in the request is valid for the
+// specified private key and action, false otherwise.
+func (s *Simulator) validHNAPAuth(req *http.Request, privateKey string, action string) bool {
+	parts := strings.Fields(req.Header.Get(hnapAuthHeader))
+	if len(parts) != 2 {
+		return false
+	}
+	msg := fmt.Sprintf("%s\"%s/%s\"", parts[1], soapNamespace, action)
+	return parts[0] == s.genHMAC(privateKey, msg)
+}
+
+// Generates the HMAC using the specified key and message strings, using the
+// hash algorithm configured for the simulator.
+func (s *Simulator) genHMAC(key string, msg string) string {
+	if s.cfg.UseSHA256 {
+		return genHMACSHA256(key, msg)
+	}
+	return genHMACMD5(key, msg)
+}
+
+// Returns a copy of the specified unpacked response for the action along
+// with the result key (unless already present).
+func withResult(action string, resp map[string]interface{}, result string) map[string]interface{} {
+	res := make(map[string]interface{}, len(resp)+1)
+	for k, v := range resp {
+		res[k] = v
+	}
+	if _, ok := res[action+"Result"]; !ok || result != resultOK {
+		res[action+"Result"] = result
+	}
+	return res
+}
+
+// Writes the response for the specified action.
+func writeResponse(w http.ResponseWriter, action string, resp map[string]interface{}, result string) {
+	w.Header().Set("Content-Type", "application/json")
+	body := map[string]interface{}{
+		action + "Response": withResult(action, resp, result),
+	}
+	if err := json.NewEncoder(w).Encode(body); err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+	}
+}
+
+// Returns a random upper case hex string generated from the specified
+// number of random bytes.
+func randomHex(n int) string {
+	b := make([]byte, n)
+	if _, err := rand.Read(b); err != nil {
+		panic(fmt.Sprintf("unable to generate random bytes, reason: %s", err))
+	}
+	return strings.ToUpper(hex.EncodeToString(b))
+}
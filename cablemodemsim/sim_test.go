@@ -0,0 +1,21 @@
+package cablemodemsim
+
+import (
+	"testing"
+)
+
+func TestNewCopiesResponses(t *testing.T) {
+	responses := map[string]map[string]interface{}{
+		"GetArrisRegisterInfo": {"ModelName": "S33"},
+	}
+	sim := New(&Config{Username: "admin", Password: "password", Responses: responses})
+
+	sim.SetResponse("GetArrisRegisterInfo", map[string]interface{}{"ModelName": "SB8200"})
+	sim.SetResponse("GetArrisDeviceStatus", map[string]interface{}{})
+	if len(responses) != 1 || responses["GetArrisRegisterInfo"]["ModelName"] != "S33" {
+		t.Errorf("SetResponse() modified the responses in the config: %v", responses)
+	}
+	if _, ok := responses["GetArrisRegisterInfo"]["GetArrisRegisterInfoResult"]; ok {
+		t.Errorf("New() modified the responses in the config: %v", responses)
+	}
+}
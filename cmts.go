@@ -0,0 +1,57 @@
+package cablemodemutil
+
+import (
+	"strings"
+	"sync"
+	"time"
+)
+
+// CMTSChange contains the details of a change in the CMTS serving the cable
+// modem.
+type CMTSChange struct {
+	// MAC address of the previously serving CMTS.
+	Previous string
+	// MAC address of the currently serving CMTS.
+	Current string
+	// System time on the device in the status where the change was observed.
+	ObservedAt time.Time
+}
+
+// CMTSTracker tracks the CMTS serving the cable modem across successive
+// status snapshots and reports when it changes, which usually indicates the
+// cable modem being moved to a different node by the ISP.
+type CMTSTracker struct {
+	current string
+	mu      sync.Mutex
+}
+
+// Update updates the tracker with the specified status, and returns the
+// change if the serving CMTS differs from the one in the previous status,
+// nil otherwise. Statuses without the CMTS MAC address are ignored.
+func (t *CMTSTracker) Update(st *CableModemStatus) *CMTSChange {
+	mac := strings.ToLower(st.Connection.CMTSMACAddress)
+	if mac == "" {
+		return nil
+	}
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	prev := t.current
+	t.current = mac
+	if prev == "" || prev == mac {
+		return nil
+	}
+	return &CMTSChange{
+		Previous:   prev,
+		Current:    mac,
+		ObservedAt: st.Connection.SystemTime,
+	}
+}
+
+// Current returns the MAC address of the CMTS in the latest status with the
+// information, empty if none.
+func (t *CMTSTracker) Current() string {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	return t.current
+}
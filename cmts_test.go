@@ -0,0 +1,31 @@
+package cablemodemutil
+
+import (
+	"testing"
+)
+
+func TestCMTSTracker(t *testing.T) {
+	steps := []struct {
+		mac         string
+		wantChanged bool
+	}{
+		{mac: "00:11:22:33:44:55", wantChanged: false},
+		{mac: "", wantChanged: false},
+		{mac: "00:11:22:33:44:55", wantChanged: false},
+		{mac: "00:11:22:33:44:66", wantChanged: true},
+		{mac: "00:11:22:33:44:66", wantChanged: false},
+	}
+
+	tracker := CMTSTracker{}
+	for i, step := range steps {
+		st := CableModemStatus{}
+		st.Connection.CMTSMACAddress = step.mac
+		change := tracker.Update(&st)
+		if (change != nil) != step.wantChanged {
+			t.Errorf("step %d: Update(%q) = %+v want changed: %t", i, step.mac, change, step.wantChanged)
+		}
+	}
+	if got := tracker.Current(); got != "00:11:22:33:44:66" {
+		t.Errorf("Current() = %q want: %q", got, "00:11:22:33:44:66")
+	}
+}
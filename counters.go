@@ -0,0 +1,152 @@
+package cablemodemutil
+
+import (
+	"math"
+	"sync"
+	"time"
+)
+
+// Counter values in the top quarter of the uint32 range followed by values in
+// the bottom quarter are considered a wraparound rather than a reset.
+const (
+	counterWrapHigh = math.MaxUint32 / 4 * 3
+	counterWrapLow  = math.MaxUint32 / 4
+)
+
+// ChannelCounterDelta contains the increase in the error counters of a
+// downstream channel between successive statuses.
+type ChannelCounterDelta struct {
+	// Channel ID in the current status.
+	ChannelID uint32
+	// Frequency identifying the channel across the statuses.
+	FrequencyHZ float32
+	// Increase in the corrected errors.
+	CorrectedErrors uint32
+	// Increase in the uncorrected errors.
+	UncorrectedErrors uint32
+	// Corrected errors per second, 0 if the elapsed time is unknown.
+	CorrectedErrorsPerSecond float64
+	// Uncorrected errors per second, 0 if the elapsed time is unknown.
+	UncorrectedErrorsPerSecond float64
+	// True if the counters were reset since the previous status, in which
+	// case the increase is the current counter value.
+	Reset bool
+	// True if any of the counters wrapped around since the previous status.
+	Wrapped bool
+}
+
+// CounterDeltas contains the increase in the error counters of the
+// downstream channels between successive statuses.
+type CounterDeltas struct {
+	// Time elapsed between the statuses (or since the reboot if the cable
+	// modem rebooted), 0 if unknown.
+	Elapsed time.Duration
+	// True if the cable modem rebooted since the previous status, detected
+	// by a decrease in the uptime.
+	Rebooted bool
+	// Deltas for the channels present in both the statuses, in the order of
+	// the channels in the current status.
+	Channels []ChannelCounterDelta
+}
+
+// The counters of a downstream channel.
+type channelCounters struct {
+	corrected   uint32
+	uncorrected uint32
+}
+
+// CounterTracker tracks the error counters of the downstream channels across
+// successive statuses, and computes the deltas and rates while accounting for
+// counter resets and wraparounds. Channels are identified by their frequency
+// rather than the channel ID, since the channel IDs can change when the
+// channels are re-bonded.
+type CounterTracker struct {
+	prev       map[uint64]channelCounters
+	upTime     time.Duration
+	systemTime time.Time
+	mu         sync.Mutex
+}
+
+// Update updates the tracker with the specified status, and returns the
+// deltas since the previous status, nil for the first status.
+func (t *CounterTracker) Update(st *CableModemStatus) *CounterDeltas {
+	cur := make(map[uint64]channelCounters)
+	for _, c := range st.Connection.Downstream.Channels {
+		cur[frequencyKey(c.FrequencyHZ)] = channelCounters{corrected: c.CorrectedErrors, uncorrected: c.UncorrectedErrors}
+	}
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	prev := t.prev
+	prevUpTime := t.upTime
+	prevSystemTime := t.systemTime
+	t.prev = cur
+	t.upTime = st.Connection.UpTime
+	t.systemTime = st.Connection.SystemTime
+	if prev == nil {
+		return nil
+	}
+
+	result := CounterDeltas{}
+	result.Rebooted = st.Connection.UpTime < prevUpTime
+	switch {
+	case result.Rebooted:
+		result.Elapsed = st.Connection.UpTime
+	case st.Connection.UpTime > prevUpTime:
+		result.Elapsed = st.Connection.UpTime - prevUpTime
+	case !prevSystemTime.IsZero() && st.Connection.SystemTime.After(prevSystemTime):
+		result.Elapsed = st.Connection.SystemTime.Sub(prevSystemTime)
+	}
+
+	for _, c := range st.Connection.Downstream.Channels {
+		p, ok := prev[frequencyKey(c.FrequencyHZ)]
+		if !ok {
+			continue
+		}
+		d := ChannelCounterDelta{
+			ChannelID:   c.ChannelID,
+			FrequencyHZ: c.FrequencyHZ,
+		}
+		var resetCorrected, resetUncorrected, wrapCorrected, wrapUncorrected bool
+		d.CorrectedErrors, resetCorrected, wrapCorrected = counterDelta(p.corrected, c.CorrectedErrors, result.Rebooted)
+		d.UncorrectedErrors, resetUncorrected, wrapUncorrected = counterDelta(
+			p.uncorrected, c.UncorrectedErrors, result.Rebooted)
+		// The counters of a channel are reset together.
+		if resetCorrected || resetUncorrected {
+			d.Reset = true
+			d.CorrectedErrors = c.CorrectedErrors
+			d.UncorrectedErrors = c.UncorrectedErrors
+		} else {
+			d.Wrapped = wrapCorrected || wrapUncorrected
+		}
+		if result.Elapsed > 0 {
+			d.CorrectedErrorsPerSecond = float64(d.CorrectedErrors) / result.Elapsed.Seconds()
+			d.UncorrectedErrorsPerSecond = float64(d.UncorrectedErrors) / result.Elapsed.Seconds()
+		}
+		result.Channels = append(result.Channels, d)
+	}
+	return &result
+}
+
+// Returns the increase from the previous to the current counter value, along
+// with whether the counter was reset or wrapped around. A decrease is
+// considered a wraparound if the previous value was close to the top of the
+// uint32 range and the current value is close to the bottom, and a reset
+// otherwise.
+func counterDelta(prev uint32, cur uint32, rebooted bool) (uint32, bool, bool) {
+	switch {
+	case rebooted:
+		return cur, true, false
+	case cur >= prev:
+		return cur - prev, false, false
+	case prev >= counterWrapHigh && cur <= counterWrapLow:
+		// Unsigned arithmetic accounts for the wraparound.
+		return cur - prev, false, true
+	}
+	return cur, true, false
+}
+
+// Returns the key identifying a channel by its frequency.
+func frequencyKey(freqHZ float32) uint64 {
+	return uint64(math.Round(float64(freqHZ)))
+}
@@ -0,0 +1,125 @@
+package cablemodemutil
+
+import (
+	"math"
+	"testing"
+	"time"
+)
+
+// Returns a status with the specified uptime and downstream channels.
+func counterTestStatus(upTime time.Duration, channels ...DownstreamChannelInfo) *CableModemStatus {
+	st := CableModemStatus{}
+	st.Connection.UpTime = upTime
+	st.Connection.Downstream.Channels = channels
+	return &st
+}
+
+func TestCounterTrackerUpdate(t *testing.T) {
+	var tracker CounterTracker
+	got := tracker.Update(counterTestStatus(
+		time.Hour,
+		DownstreamChannelInfo{ChannelID: 1, FrequencyHZ: 585000000, CorrectedErrors: 100, UncorrectedErrors: 10},
+		DownstreamChannelInfo{ChannelID: 2, FrequencyHZ: 591000000, CorrectedErrors: math.MaxUint32 - 9},
+		DownstreamChannelInfo{ChannelID: 3, FrequencyHZ: 597000000, CorrectedErrors: 500, UncorrectedErrors: 50},
+		DownstreamChannelInfo{ChannelID: 4, FrequencyHZ: 603000000},
+	))
+	if got != nil {
+		t.Fatalf("Update() for the first status = %+v want: nil", got)
+	}
+
+	// Channels 1 and 2 are re-bonded with swapped channel IDs, channel 4 is
+	// replaced by channel 5.
+	got = tracker.Update(counterTestStatus(
+		time.Hour+10*time.Second,
+		DownstreamChannelInfo{ChannelID: 2, FrequencyHZ: 585000000, CorrectedErrors: 150, UncorrectedErrors: 30},
+		DownstreamChannelInfo{ChannelID: 1, FrequencyHZ: 591000000, CorrectedErrors: 10},
+		DownstreamChannelInfo{ChannelID: 3, FrequencyHZ: 597000000, CorrectedErrors: 20, UncorrectedErrors: 1},
+		DownstreamChannelInfo{ChannelID: 5, FrequencyHZ: 609000000},
+	))
+	if got == nil {
+		t.Fatalf("Update() returned nil for the second status")
+	}
+	if got.Rebooted || got.Elapsed != 10*time.Second {
+		t.Errorf("Update() Rebooted = %t Elapsed = %s want: false 10s", got.Rebooted, got.Elapsed)
+	}
+	want := []ChannelCounterDelta{
+		{
+			ChannelID:                  2,
+			FrequencyHZ:                585000000,
+			CorrectedErrors:            50,
+			UncorrectedErrors:          20,
+			CorrectedErrorsPerSecond:   5,
+			UncorrectedErrorsPerSecond: 2,
+		},
+		{
+			ChannelID:                1,
+			FrequencyHZ:              591000000,
+			CorrectedErrors:          20,
+			CorrectedErrorsPerSecond: 2,
+			Wrapped:                  true,
+		},
+		{
+			ChannelID:                  3,
+			FrequencyHZ:                597000000,
+			CorrectedErrors:            20,
+			UncorrectedErrors:          1,
+			CorrectedErrorsPerSecond:   2,
+			UncorrectedErrorsPerSecond: 0.1,
+			Reset:                      true,
+		},
+	}
+	if len(got.Channels) != len(want) {
+		t.Fatalf("Update() returned %d channels want: %d, got: %+v", len(got.Channels), len(want), got.Channels)
+	}
+	for i := range want {
+		if got.Channels[i] != want[i] {
+			t.Errorf("Update() channel[%d] = %+v want: %+v", i, got.Channels[i], want[i])
+		}
+	}
+
+	// The cable modem reboots.
+	got = tracker.Update(counterTestStatus(
+		20*time.Second,
+		DownstreamChannelInfo{ChannelID: 1, FrequencyHZ: 585000000, CorrectedErrors: 400, UncorrectedErrors: 40},
+	))
+	if !got.Rebooted || got.Elapsed != 20*time.Second {
+		t.Errorf("Update() Rebooted = %t Elapsed = %s want: true 20s", got.Rebooted, got.Elapsed)
+	}
+	wantReboot := ChannelCounterDelta{
+		ChannelID:                  1,
+		FrequencyHZ:                585000000,
+		CorrectedErrors:            400,
+		UncorrectedErrors:          40,
+		CorrectedErrorsPerSecond:   20,
+		UncorrectedErrorsPerSecond: 2,
+		Reset:                      true,
+	}
+	if len(got.Channels) != 1 || got.Channels[0] != wantReboot {
+		t.Errorf("Update() after reboot = %+v want: [%+v]", got.Channels, wantReboot)
+	}
+}
+
+func TestCounterDelta(t *testing.T) {
+	tests := []struct {
+		name      string
+		prev      uint32
+		cur       uint32
+		rebooted  bool
+		want      uint32
+		wantReset bool
+		wantWrap  bool
+	}{
+		{"increase", 10, 25, false, 15, false, false},
+		{"unchanged", 10, 10, false, 0, false, false},
+		{"wraparound", math.MaxUint32 - 4, 5, false, 10, false, true},
+		{"decrease is a reset", 1000, 5, false, 5, true, false},
+		{"reboot", 10, 25, true, 25, true, false},
+	}
+	for _, tc := range tests {
+		got, reset, wrap := counterDelta(tc.prev, tc.cur, tc.rebooted)
+		if got != tc.want || reset != tc.wantReset || wrap != tc.wantWrap {
+			t.Errorf("%q: counterDelta(%d, %d, %t) = (%d, %t, %t) want: (%d, %t, %t)",
+				tc.name, tc.prev, tc.cur, tc.rebooted, got, reset, wrap, tc.want, tc.wantReset, tc.wantWrap)
+		}
+	}
+}
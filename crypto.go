@@ -3,15 +3,77 @@ package cablemodemutil
 import (
 	"crypto/hmac"
 	"crypto/md5" // nolint:gosec
+	"crypto/sha256"
 	"fmt"
+	"hash"
 	"io"
 )
 
+// HashAlgorithm identifies the HMAC hash algorithm used for the HNAP1 login
+// handshake and the HNAP auth of the requests.
+type HashAlgorithm int
+
+const (
+	// HashAlgorithmAuto negotiates the hash algorithm by logging in using
+	// HMAC-MD5, and retrying using HMAC-SHA256 if the login challenge
+	// request is rejected (the wrong password is not retried).
+	// The algorithm which succeeded last is tried first in the subsequent
+	// logins.
+	HashAlgorithmAuto HashAlgorithm = iota
+	// HashAlgorithmMD5 identifies HMAC-MD5, used by most of the firmware.
+	HashAlgorithmMD5
+	// HashAlgorithmSHA256 identifies HMAC-SHA256, used by the newer
+	// firmware.
+	HashAlgorithmSHA256
+)
+
+// String returns the string representation of the hash algorithm.
+func (a HashAlgorithm) String() string {
+	switch a {
+	case HashAlgorithmAuto:
+		return "Auto"
+	case HashAlgorithmMD5:
+		return "MD5"
+	case HashAlgorithmSHA256:
+		return "SHA256"
+	}
+	return "Unknown"
+}
+
+// Returns the hash algorithms to try while logging in, in order.
+func (a HashAlgorithm) candidates() []HashAlgorithm {
+	if a == HashAlgorithmAuto {
+		return []HashAlgorithm{HashAlgorithmMD5, HashAlgorithmSHA256}
+	}
+	return []HashAlgorithm{a}
+}
+
 // Generates HMAC-MD5 using the specified key and message strings.
 func genHMACMD5(key string, msg string) (string, error) {
-	h := hmac.New(md5.New, []byte(key))
-	if _, err := io.WriteString(h, msg); err != nil {
-		return "", fmt.Errorf("HMAC MD5 generation failed, reason: %w", err)
+	return genHMACWith(md5.New, "MD5", key, msg)
+}
+
+// Generates HMAC-SHA256 using the specified key and message strings.
+func genHMACSHA256(key string, msg string) (string, error) {
+	return genHMACWith(sha256.New, "SHA256", key, msg)
+}
+
+// Generates the HMAC using the specified hash algorithm, key and message
+// strings. HashAlgorithmAuto is treated as HMAC-MD5, since the algorithm
+// is only negotiated while logging in.
+func genHMAC(alg HashAlgorithm, key string, msg string) (string, error) {
+	if alg == HashAlgorithmSHA256 {
+		return genHMACSHA256(key, msg)
+	}
+	return genHMACMD5(key, msg)
+}
+
+// Generates the HMAC using the specified hash function, key and message
+// strings, as an upper case hex string.
+func genHMACWith(h func() hash.Hash, name string, key string, msg string) (string, error) {
+	mac := hmac.New(h, []byte(key))
+	if _, err := io.WriteString(mac, msg); err != nil {
+		return "", fmt.Errorf("HMAC %s generation failed, reason: %w", name, err)
 	}
-	return fmt.Sprintf("%X", h.Sum(nil)), nil
+	return fmt.Sprintf("%X", mac.Sum(nil)), nil
 }
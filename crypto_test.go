@@ -0,0 +1,28 @@
+package cablemodemutil
+
+import (
+	"testing"
+)
+
+func TestGenHMAC(t *testing.T) {
+	tests := []struct {
+		alg  HashAlgorithm
+		want string
+	}{
+		{HashAlgorithmAuto, "750C783E6AB0B503EAA86E310A5DB738"},
+		{HashAlgorithmMD5, "750C783E6AB0B503EAA86E310A5DB738"},
+		{HashAlgorithmSHA256, "5BDCC146BF60754E6A042426089575C75A003F089D2739839DEC58B964EC3843"},
+	}
+
+	for _, test := range tests {
+		// Test vectors from RFC 2202 and RFC 4231.
+		got, err := genHMAC(test.alg, "Jefe", "what do ya want for nothing?")
+		if err != nil {
+			t.Errorf("%s: genHMAC() failed, reason: %s", test.alg, err)
+			continue
+		}
+		if got != test.want {
+			t.Errorf("%s: genHMAC() = %q want: %q", test.alg, got, test.want)
+		}
+	}
+}
@@ -0,0 +1,172 @@
+package cablemodemutil
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"sort"
+	"strings"
+	"sync"
+)
+
+// DefaultModel is the model of the cable modem assumed when unspecified in
+// the RetrieverInput.
+const DefaultModel = "S33"
+
+// Driver implements the support for retrieving the status from a family of
+// cable modems. Drivers are registered using RegisterDriver and are selected
+// by the model specified in the RetrieverInput.
+//
+// Drivers may additionally implement ActionDriver and ConfigurationDriver if
+// they support those operations.
+type Driver interface {
+	// Capabilities returns the capabilities of the driver.
+	Capabilities() DriverCapabilities
+	// Authenticate logs in to the cable modem, discarding the current
+	// session if any. Drivers log in automatically while fetching the
+	// status, so calling this is only required for verifying the
+	// credentials upfront.
+	Authenticate(ctx context.Context) error
+	// FetchRaw fetches the raw status for the specified sections from the
+	// cable modem, logging in if required.
+	FetchRaw(ctx context.Context, sections StatusSection) (CableModemRawStatus, error)
+	// Parse parses the raw status fetched by the driver into the structured
+	// status, limited to the specified sections.
+	Parse(raw CableModemRawStatus, sections StatusSection) (*CableModemStatus, error)
+}
+
+// ActionDriver is implemented by the drivers supporting raw HNAP1 SOAP
+// actions.
+type ActionDriver interface {
+	// Do sends the specified SOAP action containing the specified payload
+	// within an authenticated session.
+	Do(ctx context.Context, action string, payload map[string]string) (ActionResponse, error)
+	// GetMultiple queries the specified actions in a single request within
+	// an authenticated session.
+	GetMultiple(ctx context.Context, actions ...string) (map[string]ActionResponse, error)
+}
+
+// ConfigurationDriver is implemented by the drivers supporting changing the
+// configurable settings and rebooting the cable modem.
+type ConfigurationDriver interface {
+	// SetFrontPanelLights turns the front panel LED lights on or off.
+	SetFrontPanelLights(ctx context.Context, on bool) error
+	// SetEnergyEfficientEthernet turns the energy efficient ethernet
+	// setting on or off.
+	SetEnergyEfficientEthernet(ctx context.Context, on bool) error
+	// Reboot reboots the cable modem.
+	Reboot(ctx context.Context) error
+}
+
+// DriverCapabilities contains the capabilities of a driver.
+type DriverCapabilities struct {
+	// Vendor of the cable modems supported by the driver.
+	Vendor string
+	// Models supported by the driver.
+	Models []string
+	// Status sections supported by the driver. Unsupported sections are
+	// left unpopulated in the status.
+	Sections StatusSection
+	// True if the driver implements ActionDriver.
+	Actions bool
+	// True if the driver implements ConfigurationDriver.
+	Configuration bool
+}
+
+// DriverConfig contains the configuration for building a driver, derived from
+// the RetrieverInput.
+type DriverConfig struct {
+	// The host name or IP address of the cable modem device.
+	Host string
+	// The protocol used to connect to the cable modem, either "http" or
+	// "https".
+	Protocol string
+	// HTTP client for all the requests sent to the cable modem, built
+	// according to the HTTP options in the RetrieverInput.
+	HTTPClient *http.Client
+	// User name for authenticating with the cable modem.
+	Username string
+	// Password for authenticating with the cable modem.
+	ClearPassword string
+	// Optional store for persisting the authenticated session across
+	// process restarts.
+	TokenStore TokenStore
+	// Hash algorithm used for authenticating with the HNAP1 cable modems.
+	HashAlgorithm HashAlgorithm
+	// Debugging options.
+	Debug RetrieverDebug
+}
+
+// DriverFactory builds a driver using the specified configuration.
+type DriverFactory func(cfg *DriverConfig) Driver
+
+var (
+	// nolint:gochecknoglobals
+	driversMu sync.RWMutex
+	// Registered driver factories keyed by the upper case model.
+	// nolint:gochecknoglobals
+	drivers = map[string]DriverFactory{
+		"S33":    newArrisDriver,
+		"MB8600": newMotorolaDriverFactory("MB8600"),
+		"MB8611": newMotorolaDriverFactory("MB8611"),
+		"SB6183": newHTMLDriverFactory(arrisHTMLVariant, "SB6183"),
+		"SB8200": newHTMLDriverFactory(arrisHTMLVariant, "SB8200"),
+		"CM600":  newHTMLDriverFactory(netgearHTMLVariant, "CM600"),
+		"CM700":  newHTMLDriverFactory(netgearHTMLVariant, "CM700"),
+	}
+)
+
+// RegisterDriver registers the driver factory for the specified model
+// (case insensitive), replacing the factory registered previously for the
+// model if any.
+func RegisterDriver(model string, factory DriverFactory) {
+	driversMu.Lock()
+	defer driversMu.Unlock()
+	drivers[strings.ToUpper(model)] = factory
+}
+
+// RegisteredModels returns the models with a registered driver in sorted
+// order.
+func RegisteredModels() []string {
+	driversMu.RLock()
+	defer driversMu.RUnlock()
+	res := make([]string, 0, len(drivers))
+	for model := range drivers {
+		res = append(res, model)
+	}
+	sort.Strings(res)
+	return res
+}
+
+// Returns the driver factory registered for the specified model.
+func lookupDriver(model string) (DriverFactory, error) {
+	driversMu.RLock()
+	defer driversMu.RUnlock()
+	factory, ok := drivers[strings.ToUpper(model)]
+	if !ok {
+		return nil, fmt.Errorf("%w: no driver registered for model %q", ErrUnsupported, model)
+	}
+	return factory, nil
+}
+
+// A driver which fails all the operations with the specified error, used
+// when the driver could not be built.
+type errDriver struct {
+	err error
+}
+
+func (d *errDriver) Capabilities() DriverCapabilities {
+	return DriverCapabilities{}
+}
+
+func (d *errDriver) Authenticate(ctx context.Context) error {
+	return d.err
+}
+
+func (d *errDriver) FetchRaw(ctx context.Context, sections StatusSection) (CableModemRawStatus, error) {
+	return nil, d.err
+}
+
+func (d *errDriver) Parse(raw CableModemRawStatus, sections StatusSection) (*CableModemStatus, error) {
+	return nil, d.err
+}
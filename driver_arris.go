@@ -0,0 +1,226 @@
+package cablemodemutil
+
+import (
+	"context"
+	"fmt"
+)
+
+const (
+	getConfigurationSubCommand = "GetArrisConfigurationInfo"
+	setConfigurationAction     = "SetArrisConfigurationInfo"
+	// Value of the "Action" field in the configuration request to apply
+	// the configurable settings.
+	applyConfigurationAction = "apply"
+	// Value of the "Action" field in the configuration request to reboot
+	// the cable modem.
+	rebootConfigurationAction = "reboot"
+)
+
+// Returns the string representation of the specified setting used in the
+// configuration request.
+func configurationFlag(on bool) string {
+	if on {
+		return "1"
+	}
+	return "0"
+}
+
+// The status sub-commands queried using GetMultipleHNAPs.
+// nolint:gochecknoglobals
+var statusSubCommands = []string{
+	// MAC address, serial number and model info.
+	"GetArrisRegisterInfo",
+	// Software version info along with device MAC addr / serial.
+	"GetCustomerStatusSoftware",
+	// Short Device Status and signal info.
+	"GetArrisDeviceStatus",
+	// Current Time, uptime and short connection status.
+	"GetCustomerStatusConnectionInfo",
+	// Detailed connection status.
+	"GetCustomerStatusStartupSequence",
+	// Downstream channel info.
+	"GetCustomerStatusDownstreamChannelInfo",
+	// Upstream channel info.
+	"GetCustomerStatusUpstreamChannelInfo",
+	// Downstream/Upstream Frequency summary and configurable settings.
+	"GetArrisConfigurationInfo",
+	// Event log.
+	"GetCustomerStatusLog",
+	// User login/password information (Not so useful).
+	"GetCustomerStatusSecAccount",
+	// Ask me later and never ask (Not so useful).
+	"GetArrisRegisterStatus",
+	// DOCSIS 3.1 OFDM downstream channel info (not retrieved by default).
+	"GetCustomerStatusDownstreamOFDMChannelInfo",
+	// DOCSIS 3.1 OFDMA upstream channel info (not retrieved by default).
+	"GetCustomerStatusUpstreamOFDMAChannelInfo",
+}
+
+// The sub-commands required for retrieving each of the status sections.
+// nolint:gochecknoglobals
+var sectionSubCommands = map[StatusSection][]string{
+	SectionInfo:                   {"GetArrisRegisterInfo"},
+	SectionSettings:               {"GetArrisConfigurationInfo", "GetArrisRegisterStatus"},
+	SectionAuth:                   {"GetCustomerStatusSecAccount"},
+	SectionSoftware:               {"GetCustomerStatusSoftware"},
+	SectionStartup:                {"GetCustomerStatusStartupSequence"},
+	SectionDownstreamChannels:     {"GetCustomerStatusDownstreamChannelInfo"},
+	SectionUpstreamChannels:       {"GetCustomerStatusUpstreamChannelInfo"},
+	SectionLogs:                   {"GetCustomerStatusLog"},
+	SectionOFDMDownstreamChannels: {"GetCustomerStatusDownstreamOFDMChannelInfo"},
+	SectionOFDMAUpstreamChannels:  {"GetCustomerStatusUpstreamOFDMAChannelInfo"},
+	SectionConnection: {
+		"GetCustomerStatusConnectionInfo",
+		"GetArrisDeviceStatus",
+		"GetArrisConfigurationInfo",
+	},
+}
+
+// Returns the sub-commands required for retrieving the specified status
+// sections, in the same order as statusSubCommands.
+func statusSubCommandsFor(sections StatusSection) []string {
+	required := make(map[string]bool)
+	for section, cmds := range sectionSubCommands {
+		if !sections.Has(section) {
+			continue
+		}
+		for _, cmd := range cmds {
+			required[cmd] = true
+		}
+	}
+
+	var result []string
+	for _, cmd := range statusSubCommands {
+		if required[cmd] {
+			result = append(result, cmd)
+		}
+	}
+	return result
+}
+
+// Driver for the Arris cable modems using HNAP1 (Eg. Arris S33).
+type arrisDriver struct {
+	session *hnapSession
+}
+
+// Returns a new driver for the Arris HNAP1 cable modems.
+func newArrisDriver(cfg *DriverConfig) Driver {
+	return &arrisDriver{session: newHNAPSession(cfg)}
+}
+
+// Capabilities returns the capabilities of the driver.
+func (d *arrisDriver) Capabilities() DriverCapabilities {
+	return DriverCapabilities{
+		Vendor:        "Arris",
+		Models:        []string{"S33"},
+		Sections:      AllSections,
+		Actions:       true,
+		Configuration: true,
+	}
+}
+
+// Authenticate logs in to the cable modem, discarding the current session if
+// any.
+func (d *arrisDriver) Authenticate(ctx context.Context) error {
+	return d.session.authenticate(ctx)
+}
+
+// FetchRaw fetches the raw status for the specified sections from the cable
+// modem using a single GetMultipleHNAPs request.
+func (d *arrisDriver) FetchRaw(ctx context.Context, sections StatusSection) (CableModemRawStatus, error) {
+	return d.session.queryRawStatus(ctx, statusSubCommandsFor(sections))
+}
+
+// Parse parses the raw status into the structured status.
+func (d *arrisDriver) Parse(raw CableModemRawStatus, sections StatusSection) (*CableModemStatus, error) {
+	return ParseRawStatusWithOptions(raw, &StatusOptions{Sections: sections})
+}
+
+// Do sends the specified SOAP action containing the specified payload within
+// an authenticated session.
+func (d *arrisDriver) Do(ctx context.Context, action string, payload map[string]string) (ActionResponse, error) {
+	return d.session.do(ctx, action, payload, true)
+}
+
+// GetMultiple queries the specified actions in a single GetMultipleHNAPs
+// request within an authenticated session.
+func (d *arrisDriver) GetMultiple(ctx context.Context, actions ...string) (map[string]ActionResponse, error) {
+	return d.session.getMultiple(ctx, actions...)
+}
+
+// Retrieves the current configurable settings from the cable modem.
+func (d *arrisDriver) getConfiguration(ctx context.Context) (*DeviceSettings, error) {
+	resp, err := d.session.getMultiple(ctx, getConfigurationSubCommand)
+	if err != nil {
+		return nil, err
+	}
+	conf := actionResponseBody(resp[getConfigurationSubCommand])
+
+	result := DeviceSettings{}
+	result.FrontPanelLightsOn, err = parseBool(conf, "LedStatus", "1", "LED Status")
+	if err != nil {
+		return nil, err
+	}
+	result.EnergyEfficientEthernetOn, err = parseBool(conf, "ethSWEthEEE", "1", "Energy Efficient Ethernet")
+	if err != nil {
+		return nil, err
+	}
+	return &result, nil
+}
+
+// Sends the configuration request with the specified action and settings to
+// the cable modem.
+func (d *arrisDriver) setConfiguration(ctx context.Context, action string, settings *DeviceSettings) error {
+	payload := map[string]string{
+		"Action":       action,
+		"SetEEEEnable": configurationFlag(settings.EnergyEfficientEthernetOn),
+		"LED_Status":   configurationFlag(settings.FrontPanelLightsOn),
+	}
+	// The configuration requests (including the reboot) are not idempotent,
+	// and are hence never re-sent. The session is refreshed by retrieving
+	// the current configuration beforehand.
+	_, err := d.session.do(ctx, setConfigurationAction, payload, false)
+	if err != nil {
+		return fmt.Errorf("failed to set configuration (action: %q), reason: %w", action, err)
+	}
+	return nil
+}
+
+// SetFrontPanelLights turns the front panel LED lights of the cable modem on
+// or off. The rest of the configurable settings are retained as is.
+func (d *arrisDriver) SetFrontPanelLights(ctx context.Context, on bool) error {
+	settings, err := d.getConfiguration(ctx)
+	if err != nil {
+		return err
+	}
+	settings.FrontPanelLightsOn = on
+	return d.setConfiguration(ctx, applyConfigurationAction, settings)
+}
+
+// SetEnergyEfficientEthernet turns the energy efficient ethernet setting of
+// the cable modem on or off. The rest of the configurable settings are
+// retained as is.
+func (d *arrisDriver) SetEnergyEfficientEthernet(ctx context.Context, on bool) error {
+	settings, err := d.getConfiguration(ctx)
+	if err != nil {
+		return err
+	}
+	settings.EnergyEfficientEthernetOn = on
+	return d.setConfiguration(ctx, applyConfigurationAction, settings)
+}
+
+// Reboot reboots the cable modem. The configurable settings are retained as
+// is. The current session is discarded since it does not survive the
+// reboot, and the next request logs in again.
+func (d *arrisDriver) Reboot(ctx context.Context) error {
+	settings, err := d.getConfiguration(ctx)
+	if err != nil {
+		return err
+	}
+	err = d.setConfiguration(ctx, rebootConfigurationAction, settings)
+	if err != nil {
+		return err
+	}
+	d.session.reset()
+	return nil
+}
@@ -0,0 +1,857 @@
+package cablemodemutil
+
+import (
+	"context"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	arrisConnectionStatusPage = "/cmconnectionstatus.html"
+	arrisSoftwareInfoPage     = "/cmswinfo.html"
+	arrisEventLogPage         = "/cmeventlog.html"
+	netgearDocsisStatusPage   = "/DocsisStatus.htm"
+	// Name of the cookie containing the credential for the cookie based
+	// login.
+	credentialCookie = "credential"
+	// Maximum length of the credential for the cookie based login.
+	maxCredentialLength = 256
+	// Value of the log timestamp for the entries logged before the time
+	// was established with the CMTS.
+	timeNotEstablished = "Time Not Established"
+)
+
+// Layouts of the log timestamps in the event log pages.
+// nolint:gochecknoglobals
+var htmlLogTimestampFormats = []string{
+	"1/2/2006 15:04:05",
+	"1/2/2006 15:04",
+	systemTimestampFormat,
+}
+
+// Identifies the scheme used for logging in to the cable modems serving
+// the status as HTML pages.
+type htmlAuthScheme int
+
+const (
+	// No login is required for accessing the pages.
+	htmlAuthNone htmlAuthScheme = iota
+	// HTTP basic authentication on every request.
+	htmlAuthBasic
+	// The credentials are exchanged for a credential cookie upfront, as
+	// seen with the newer Arris SB series firmware.
+	htmlAuthCookie
+)
+
+// Describes a family of cable modems serving the status as HTML pages.
+type htmlVariant struct {
+	vendor string
+	models []string
+	auth   htmlAuthScheme
+	// The page requested for logging in and verifying the credentials.
+	authPage string
+	// The pages required for retrieving each of the supported status
+	// sections.
+	sectionPages map[StatusSection][]string
+	// If non-nil, returns the tables populated by the scripts in the page,
+	// which take precedence over the tables in the page.
+	scriptTables func(page string) []htmlTable
+}
+
+var (
+	// Arris SB series cable modems (Eg. Arris SB6183 and SB8200). The older
+	// firmware does not require a login (and ignores the login request),
+	// while the newer firmware uses the credential cookie.
+	// nolint:gochecknoglobals
+	arrisHTMLVariant = &htmlVariant{
+		vendor:   "Arris",
+		models:   []string{"SB6183", "SB8200"},
+		auth:     htmlAuthCookie,
+		authPage: arrisConnectionStatusPage,
+		sectionPages: map[StatusSection][]string{
+			SectionInfo:               {arrisSoftwareInfoPage},
+			SectionSoftware:           {arrisSoftwareInfoPage},
+			SectionStartup:            {arrisConnectionStatusPage},
+			SectionConnection:         {arrisConnectionStatusPage, arrisSoftwareInfoPage},
+			SectionDownstreamChannels: {arrisConnectionStatusPage},
+			SectionUpstreamChannels:   {arrisConnectionStatusPage},
+			SectionLogs:               {arrisEventLogPage},
+		},
+	}
+	// Netgear CM series cable modems (Eg. Netgear CM600 and CM700). The
+	// tables in the status page are populated by the scripts in the page.
+	// nolint:gochecknoglobals
+	netgearHTMLVariant = &htmlVariant{
+		vendor:   "Netgear",
+		models:   []string{"CM600", "CM700"},
+		auth:     htmlAuthBasic,
+		authPage: netgearDocsisStatusPage,
+		sectionPages: map[StatusSection][]string{
+			SectionStartup:            {netgearDocsisStatusPage},
+			SectionConnection:         {netgearDocsisStatusPage},
+			SectionDownstreamChannels: {netgearDocsisStatusPage},
+			SectionUpstreamChannels:   {netgearDocsisStatusPage},
+		},
+		scriptTables: parseNetgearTagValueTables,
+	}
+)
+
+// Returns the status sections supported by the variant.
+func (v *htmlVariant) sections() StatusSection {
+	var result StatusSection
+	for section := range v.sectionPages {
+		result |= section
+	}
+	return result
+}
+
+// Returns the pages required for retrieving the specified status sections,
+// in a deterministic order.
+func (v *htmlVariant) pagesFor(sections StatusSection) []string {
+	required := make(map[string]bool)
+	for section, pages := range v.sectionPages {
+		if !sections.Has(section) {
+			continue
+		}
+		for _, page := range pages {
+			required[page] = true
+		}
+	}
+
+	var result []string
+	for _, page := range []string{
+		arrisConnectionStatusPage,
+		arrisSoftwareInfoPage,
+		arrisEventLogPage,
+		netgearDocsisStatusPage,
+	} {
+		if required[page] {
+			result = append(result, page)
+		}
+	}
+	return result
+}
+
+// Driver for the cable modems without HNAP1 support, which serve the status
+// only as HTML tables. The raw status contains the HTML pages keyed by the
+// path of the page.
+type htmlDriver struct {
+	variant       *htmlVariant
+	model         string
+	client        *http.Client
+	baseURL       string
+	username      string
+	clearPassword string
+	debug         RetrieverDebug
+	// The credential obtained using the cookie based login.
+	credential string
+	// True if the firmware does not support the cookie based login.
+	noCookieAuth bool
+	credentialMu sync.Mutex
+}
+
+// Returns a factory for the drivers of the specified variant and model.
+func newHTMLDriverFactory(variant *htmlVariant, model string) DriverFactory {
+	return func(cfg *DriverConfig) Driver {
+		protocol := cfg.Protocol
+		if protocol == "" {
+			protocol = "http"
+		}
+		return &htmlDriver{
+			variant:       variant,
+			model:         model,
+			client:        cfg.HTTPClient,
+			baseURL:       fmt.Sprintf("%s://%s", protocol, cfg.Host),
+			username:      cfg.Username,
+			clearPassword: cfg.ClearPassword,
+			debug:         cfg.Debug,
+		}
+	}
+}
+
+// Capabilities returns the capabilities of the driver.
+func (d *htmlDriver) Capabilities() DriverCapabilities {
+	return DriverCapabilities{
+		Vendor:   d.variant.vendor,
+		Models:   d.variant.models,
+		Sections: d.variant.sections(),
+	}
+}
+
+// Authenticate logs in to the cable modem, discarding the current
+// credential if any. For the cable modems without a login handshake, this
+// verifies the credentials (if any) are accepted.
+func (d *htmlDriver) Authenticate(ctx context.Context) error {
+	if d.usesCookieAuth() {
+		d.credentialMu.Lock()
+		d.credential = ""
+		d.credentialMu.Unlock()
+		_, err := d.getCredential(ctx)
+		return err
+	}
+	_, err := d.fetchPage(ctx, d.variant.authPage)
+	return err
+}
+
+// FetchRaw fetches the HTML pages required for the specified sections from
+// the cable modem, logging in if required.
+func (d *htmlDriver) FetchRaw(ctx context.Context, sections StatusSection) (CableModemRawStatus, error) {
+	result := make(CableModemRawStatus)
+	for _, page := range d.variant.pagesFor(sections) {
+		body, err := d.fetchPage(ctx, page)
+		if err != nil {
+			return nil, err
+		}
+		result[page] = body
+	}
+	return result, nil
+}
+
+// Parse parses the tables in the HTML pages of the raw status into the
+// structured status. The fields not present in the pages are left as zero
+// values.
+// nolint:cyclop
+func (d *htmlDriver) Parse(raw CableModemRawStatus, sections StatusSection) (*CableModemStatus, error) {
+	sections &= d.variant.sections()
+	var tables []htmlTable
+	for _, page := range d.variant.pagesFor(sections) {
+		body, ok := raw[page].(string)
+		if !ok {
+			return nil, &ResponseError{
+				Action:   page,
+				Reason:   fmt.Sprintf("unable to find the page %q in status response", page),
+				Response: prettyPrintJSON(raw[page]),
+			}
+		}
+		if d.variant.scriptTables != nil {
+			tables = append(tables, d.variant.scriptTables(body)...)
+		}
+		tables = append(tables, parseHTMLTables(body)...)
+	}
+	kv := htmlKeyValues(tables)
+
+	var err error
+	result := CableModemStatus{Sections: sections}
+	if sections.Has(SectionInfo) {
+		err = populateHTMLDeviceInfo(kv, d.model, &result.Info)
+		if err != nil {
+			return nil, err
+		}
+	}
+	if sections.Has(SectionSoftware) {
+		err = populateHTMLSoftwareStatus(kv, &result.Software)
+		if err != nil {
+			return nil, err
+		}
+	}
+	if sections.Has(SectionStartup) {
+		err = populateHTMLStartupStatus(kv, &result.Startup)
+		if err != nil {
+			return nil, err
+		}
+	}
+	if sections.Has(SectionConnection) {
+		err = populateHTMLConnectionSummary(kv, &result.Connection)
+		if err != nil {
+			return nil, err
+		}
+	}
+	if sections.Has(SectionDownstreamChannels) {
+		result.Connection.Downstream.Channels, err = populateHTMLDownstreamChannels(tables)
+		if err != nil {
+			return nil, err
+		}
+	}
+	if sections.Has(SectionUpstreamChannels) {
+		result.Connection.Upstream.Channels, err = populateHTMLUpstreamChannels(tables)
+		if err != nil {
+			return nil, err
+		}
+	}
+	if sections.Has(SectionLogs) {
+		result.Logs, err = populateHTMLLogEntries(tables)
+		if err != nil {
+			return nil, err
+		}
+		result.LogEventCounts = countLogEvents(result.Logs)
+		populateCMTSInfo(result.Logs, &result.Connection)
+	}
+	return &result, nil
+}
+
+// Returns true if the driver logs in using the credential cookie.
+func (d *htmlDriver) usesCookieAuth() bool {
+	if d.variant.auth != htmlAuthCookie || d.username == "" {
+		return false
+	}
+	d.credentialMu.Lock()
+	defer d.credentialMu.Unlock()
+	return !d.noCookieAuth
+}
+
+// Returns true if the specified login response looks like a credential
+// (i.e. a short token without any whitespace or markup).
+func isCredential(body string) bool {
+	if body == "" || len(body) > maxCredentialLength {
+		return false
+	}
+	return strings.IndexFunc(body, func(r rune) bool {
+		return r <= ' ' || r > '~' || r == '<' || r == '>'
+	}) < 0
+}
+
+// Returns the current credential for the cookie based login, logging in if
+// there is none. Returns an empty credential if the firmware turns out to
+// not support the cookie based login, in which case the driver falls back
+// to the basic auth for the rest of the requests.
+func (d *htmlDriver) getCredential(ctx context.Context) (string, error) {
+	d.credentialMu.Lock()
+	defer d.credentialMu.Unlock()
+	if d.credential != "" {
+		return d.credential, nil
+	}
+
+	// The credentials are passed both in the query and as basic auth, and
+	// the response body is the credential.
+	auth := base64.StdEncoding.EncodeToString([]byte(d.username + ":" + d.clearPassword))
+	body, status, err := d.get(ctx, d.variant.authPage+"?login_"+auth, "")
+	if err != nil {
+		return "", err
+	}
+	if status != http.StatusOK || body == "" {
+		return "", &AuthError{
+			Username: d.username,
+			Err:      fmt.Errorf("login using page %q failed with status code %d", d.variant.authPage, status),
+		}
+	}
+	cred := strings.TrimSpace(body)
+	if !isCredential(cred) {
+		// The older firmware ignores the login query and serves the page
+		// as is.
+		if d.debug.Debug {
+			fmt.Printf("Login using page %q did not return a credential, falling back to basic auth.\n", d.variant.authPage)
+		}
+		d.noCookieAuth = true
+		return "", nil
+	}
+	d.credential = cred
+	return d.credential, nil
+}
+
+// Fetches the specified page from the cable modem, logging in again once if
+// the credential is rejected.
+func (d *htmlDriver) fetchPage(ctx context.Context, page string) (string, error) {
+	var (
+		body   string
+		status int
+	)
+	for attempt := 0; attempt < 2; attempt++ {
+		cred := ""
+		if d.usesCookieAuth() {
+			var err error
+			cred, err = d.getCredential(ctx)
+			if err != nil {
+				return "", err
+			}
+		}
+
+		var err error
+		body, status, err = d.get(ctx, page, cred)
+		if err != nil {
+			return "", err
+		}
+		if status != http.StatusUnauthorized || cred == "" {
+			break
+		}
+		// The credential has expired, discard it and log in again.
+		d.credentialMu.Lock()
+		if d.credential == cred {
+			d.credential = ""
+		}
+		d.credentialMu.Unlock()
+	}
+
+	switch {
+	case status == http.StatusUnauthorized:
+		return "", &AuthError{
+			Username: d.username,
+			Err:      fmt.Errorf("HTTP GET request for page %q failed with status code %d", page, status),
+		}
+	case status != http.StatusOK:
+		return "", fmt.Errorf(
+			"HTTP GET request for page %q failed due to non-success status code: %d\nbody:%s",
+			page,
+			status,
+			body,
+		)
+	}
+	return body, nil
+}
+
+// Sends the HTTP GET request for the specified path (including the query
+// if any), returning the response body and the status code.
+func (d *htmlDriver) get(ctx context.Context, path string, cred string) (string, int, error) {
+	url := d.baseURL + path
+	if cred != "" {
+		url += "?ct_" + cred
+	}
+	req, err := http.NewRequestWithContext(ctx, "GET", url, nil)
+	if err != nil {
+		return "", 0, fmt.Errorf("unable to create GET request, reason: %w", err)
+	}
+	switch {
+	case cred != "":
+		req.AddCookie(&http.Cookie{Name: credentialCookie, Value: cred})
+	case d.username != "" && d.variant.auth != htmlAuthNone:
+		req.SetBasicAuth(d.username, d.clearPassword)
+	}
+
+	if d.debug.DebugReq {
+		debugHTTPRequest(req)
+	}
+	resp, err := d.client.Do(req)
+	if err != nil {
+		return "", 0, fmt.Errorf("HTTP GET request for page %q failed, reason: %w", path, err)
+	}
+	defer resp.Body.Close()
+	if d.debug.DebugResp {
+		debugHTTPResponse(resp)
+	}
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return "", 0, fmt.Errorf(
+			"HTTP GET request for page %q failed while reading the response body, reason: %w",
+			path,
+			err,
+		)
+	}
+	return string(body), resp.StatusCode, nil
+}
+
+// Returns the cells following the label in the first cell of each row
+// (containing at least two cells) in the specified tables, keyed by the
+// lower case label without the trailing colon. The first occurrence of a
+// label takes precedence.
+func htmlKeyValues(tables []htmlTable) map[string][]string {
+	result := make(map[string][]string)
+	for _, t := range tables {
+		for _, row := range t {
+			if len(row) < 2 {
+				continue
+			}
+			key := strings.ToLower(strings.TrimSuffix(row[0], ":"))
+			if _, ok := result[key]; !ok {
+				result[key] = row[1:]
+			}
+		}
+	}
+	return result
+}
+
+// Returns the cell at the specified index following the specified label.
+func htmlValue(kv map[string][]string, label string, index int, desc string) (string, error) {
+	vals, ok := kv[label]
+	if !ok || len(vals) <= index {
+		return "", &ParseError{
+			Desc: desc,
+			Key:  label,
+			Err:  errors.New("unable to find the field in the status pages"),
+		}
+	}
+	return vals[index], nil
+}
+
+// Returns the value in the specified cell stripped of the units
+// (Eg. "507000000 Hz" or "5.4 dBmV").
+func htmlNumberStr(cell string) string {
+	fields := strings.Fields(cell)
+	if len(fields) == 0 {
+		return ""
+	}
+	return fields[0]
+}
+
+// Parses the duration in either of the formats "3 days 14h:15m:33s.00" or
+// "3 days 14:15:33".
+func parseHTMLDurationStr(str string, desc string) (time.Duration, error) {
+	d, err := parseDurationStr(str, desc)
+	if err == nil {
+		return d, nil
+	}
+	days := ""
+	hms := str
+	if i := strings.LastIndex(str, " "); i >= 0 {
+		days, hms = str[:i+1], str[i+1:]
+	}
+	parts := strings.Split(hms, ":")
+	if len(parts) != 3 {
+		return 0, err
+	}
+	return parseDurationStr(days+parts[0]+"h:"+parts[1]+"m:"+parts[2]+"s", desc)
+}
+
+// Populates the device information from the software information page.
+func populateHTMLDeviceInfo(kv map[string][]string, model string, result *DeviceInfo) error {
+	var err error
+	result.Model = model
+	result.SerialNumber, err = htmlValue(kv, "serial number", 0, "Serial Number")
+	if err != nil {
+		return err
+	}
+	result.MACAddress, err = htmlValue(kv, "cable modem mac address", 0, "MAC Address")
+	if err != nil {
+		return err
+	}
+	return nil
+}
+
+// Populates the software status from the software information page.
+func populateHTMLSoftwareStatus(kv map[string][]string, result *SoftwareStatus) error {
+	var err error
+	result.FirmwareVersion, err = htmlValue(kv, "software version", 0, "Firmware Version")
+	if err != nil {
+		return err
+	}
+	result.HDVersion, err = htmlValue(kv, "hardware version", 0, "HD Version")
+	if err != nil {
+		return err
+	}
+	result.DOCSISSpecVersion, err = htmlValue(kv, "standard specification compliant", 0, "DOCSIS Spec Version")
+	if err != nil {
+		return err
+	}
+	return nil
+}
+
+// Populates the startup status from the startup procedure table, whose rows
+// contain the procedure, the status and the comment.
+// nolint:cyclop
+func populateHTMLStartupStatus(kv map[string][]string, result *StartupStatus) error {
+	// Returns the status and the comment of the specified procedure.
+	procedure := func(label string, desc string) (string, string, error) {
+		status, err := htmlValue(kv, label, 0, desc)
+		if err != nil {
+			return "", "", err
+		}
+		comment, _ := htmlValue(kv, label, 1, desc)
+		return status, comment, nil
+	}
+
+	freq, comment, err := procedure("acquire downstream channel", "Downstream Connection Frequency")
+	if err != nil {
+		return err
+	}
+	result.Downstream.FrequencyHZ, err = parseFreqStr(htmlNumberStr(freq), false, "Downstream Connection Frequency")
+	if err != nil {
+		return withParseKey(err, "acquire downstream channel")
+	}
+	result.Downstream.Locked = strings.EqualFold(comment, "Locked")
+
+	status, comment, err := procedure("connectivity state", "Connectivity Status")
+	if err != nil {
+		return err
+	}
+	result.Connectivity.Status = strings.EqualFold(status, "OK")
+	result.Connectivity.Operational = strings.EqualFold(comment, "Operational")
+
+	status, comment, err = procedure("boot state", "Boot Status")
+	if err != nil {
+		return err
+	}
+	result.Boot.Status = strings.EqualFold(status, "OK")
+	result.Boot.Operational = strings.EqualFold(comment, "Operational")
+
+	status, comment, err = procedure("configuration file", "Configuration File Status")
+	if err != nil {
+		return err
+	}
+	result.ConfigFile.Status = strings.EqualFold(status, "OK")
+	result.ConfigFile.Comment = comment
+
+	status, comment, err = procedure("security", "Security Status")
+	if err != nil {
+		return err
+	}
+	// Eg. "Enabled" or "Enable".
+	result.Security.Enabled = strings.HasPrefix(strings.ToLower(status), "enable")
+	result.Security.Comment = comment
+	return nil
+}
+
+// Populates the connection status excluding the channel information. Only
+// the fields present in the pages are populated.
+func populateHTMLConnectionSummary(kv map[string][]string, result *ConnectionStatus) error {
+	for _, label := range []string{"up time", "system up time"} {
+		upTime, err := htmlValue(kv, label, 0, "System Up Time")
+		if err != nil {
+			continue
+		}
+		result.UpTime, err = parseHTMLDurationStr(upTime, "System Up Time")
+		if err != nil {
+			return withParseKey(err, label)
+		}
+		break
+	}
+	for _, label := range []string{"network access", "docsis network access enabled"} {
+		access, err := htmlValue(kv, label, 0, "DOCSIS Network Access")
+		if err != nil {
+			continue
+		}
+		result.DOCSISNetworkAccessAllowed = strings.EqualFold(access, "Allowed")
+		break
+	}
+	if status, err := htmlValue(kv, "connectivity state", 0, "Connectivity Status"); err == nil {
+		result.InternetConnected = strings.EqualFold(status, "OK")
+	}
+	return nil
+}
+
+// Returns the index of the first of the specified columns present in the
+// header, or -1 if none.
+func htmlColumn(header map[string]int, names ...string) int {
+	for _, name := range names {
+		if i, ok := header[name]; ok {
+			return i
+		}
+	}
+	return -1
+}
+
+// Returns the cell at the specified column index in the row, or an empty
+// string if unavailable.
+func htmlCell(row []string, col int) string {
+	if col < 0 || col >= len(row) {
+		return ""
+	}
+	return row[col]
+}
+
+// Returns the rows (following the header row) of the first table whose
+// header contains all the specified columns and none of the excluded
+// columns, along with the header.
+func findHTMLTable(tables []htmlTable, columns []string, excluded []string) ([][]string, map[string]int) {
+	for _, t := range tables {
+		i, header := t.header(columns...)
+		if i < 0 || htmlColumn(header, excluded...) >= 0 {
+			continue
+		}
+		return t[i+1:], header
+	}
+	return nil, nil
+}
+
+// Populates the downstream channel information from the downstream bonded
+// channels table.
+// nolint:cyclop
+func populateHTMLDownstreamChannels(tables []htmlTable) ([]DownstreamChannelInfo, error) {
+	const dsKey = "Downstream Bonded Channels"
+	rows, header := findHTMLTable(tables, []string{"lock status", "modulation"}, []string{"us channel type"})
+	if header == nil {
+		return nil, &ParseError{
+			Desc: "Downstream Channel info",
+			Key:  dsKey,
+			Err:  errors.New("unable to find the downstream channels table"),
+		}
+	}
+	var (
+		idCol    = htmlColumn(header, "channel id")
+		lockCol  = htmlColumn(header, "lock status")
+		modCol   = htmlColumn(header, "modulation")
+		freqCol  = htmlColumn(header, "frequency")
+		powerCol = htmlColumn(header, "power")
+		snrCol   = htmlColumn(header, "snr/mer", "snr")
+		corrCol  = htmlColumn(header, "corrected", "correctables")
+		uncorCol = htmlColumn(header, "uncorrectables", "uncorrected")
+	)
+
+	var result []DownstreamChannelInfo
+	for _, row := range rows {
+		if len(row) < len(header) {
+			continue
+		}
+		var (
+			ch  DownstreamChannelInfo
+			err error
+		)
+		ch.ChannelID, err = parseChannelIDStr(htmlNumberStr(htmlCell(row, idCol)), "Downstream Channel ID")
+		if err != nil {
+			return nil, withParseKey(err, dsKey)
+		}
+		if ch.ChannelID == 0 {
+			// Unused channel slot.
+			continue
+		}
+		ch.Locked = strings.EqualFold(htmlCell(row, lockCol), "Locked")
+		ch.Modulation = htmlCell(row, modCol)
+		ch.Type = parseChannelTypeStr(ch.Modulation)
+		ch.FrequencyHZ, err = parseFreqStr(htmlNumberStr(htmlCell(row, freqCol)), false, "Downstream Channel Frequency")
+		if err != nil {
+			return nil, withParseKey(err, dsKey)
+		}
+		ch.SignalPowerDBMV, err = parseSignalPowerStr(
+			htmlNumberStr(htmlCell(row, powerCol)), false, "Downstream Channel Signal Power")
+		if err != nil {
+			return nil, withParseKey(err, dsKey)
+		}
+		ch.SignalSNRMERDB, err = parseSignalSNRStr(
+			htmlNumberStr(htmlCell(row, snrCol)), false, "Downstream Channel Signal SNR/MER")
+		if err != nil {
+			return nil, withParseKey(err, dsKey)
+		}
+		ch.CorrectedErrors, err = parseSignalErrorsStr(
+			htmlNumberStr(htmlCell(row, corrCol)), "Downstream Channel Signal Corrected Errors")
+		if err != nil {
+			return nil, withParseKey(err, dsKey)
+		}
+		ch.UncorrectedErrors, err = parseSignalErrorsStr(
+			htmlNumberStr(htmlCell(row, uncorCol)), "Downstream Channel Signal Uncorrected Errors")
+		if err != nil {
+			return nil, withParseKey(err, dsKey)
+		}
+		result = append(result, ch)
+	}
+	return result, nil
+}
+
+// Populates the upstream channel information from the upstream bonded
+// channels table.
+// nolint:cyclop,funlen
+func populateHTMLUpstreamChannels(tables []htmlTable) ([]UpstreamChannelInfo, error) {
+	const usKey = "Upstream Bonded Channels"
+	rows, header := findHTMLTable(tables, []string{"lock status", "us channel type"}, nil)
+	if header == nil {
+		return nil, &ParseError{
+			Desc: "Upstream Channel info",
+			Key:  usKey,
+			Err:  errors.New("unable to find the upstream channels table"),
+		}
+	}
+	var (
+		idCol    = htmlColumn(header, "channel id")
+		lockCol  = htmlColumn(header, "lock status")
+		typeCol  = htmlColumn(header, "us channel type")
+		rateCol  = htmlColumn(header, "symbol rate")
+		widthCol = htmlColumn(header, "width")
+		freqCol  = htmlColumn(header, "frequency")
+		powerCol = htmlColumn(header, "power")
+	)
+
+	var result []UpstreamChannelInfo
+	for _, row := range rows {
+		if len(row) < len(header) {
+			continue
+		}
+		var (
+			ch  UpstreamChannelInfo
+			err error
+		)
+		ch.ChannelID, err = parseChannelIDStr(htmlNumberStr(htmlCell(row, idCol)), "Upstream Channel ID")
+		if err != nil {
+			return nil, withParseKey(err, usKey)
+		}
+		if ch.ChannelID == 0 {
+			// Unused channel slot.
+			continue
+		}
+		ch.Locked = strings.EqualFold(htmlCell(row, lockCol), "Locked")
+		ch.Modulation = htmlCell(row, typeCol)
+		ch.Type = parseChannelTypeStr(ch.Modulation)
+		if ch.Type == ChannelTypeOFDM {
+			// Eg. "OFDM Upstream".
+			ch.Type = ChannelTypeOFDMA
+		}
+		switch {
+		case widthCol >= 0:
+			ch.WidthHZ, err = parseFreqStr(htmlNumberStr(htmlCell(row, widthCol)), false, "Upstream Channel Width")
+			if err != nil {
+				return nil, withParseKey(err, usKey)
+			}
+		case rateCol >= 0 && ch.Type == ChannelTypeSCQAM:
+			var symbolRate float32
+			symbolRate, err = parseFloat32(
+				htmlNumberStr(htmlCell(row, rateCol)), false, "", "Upstream Channel Symbol Rate")
+			if err != nil {
+				return nil, withParseKey(err, usKey)
+			}
+			ch.WidthHZ = scQAMUpstreamWidth(symbolRate)
+		}
+		ch.FrequencyHZ, err = parseFreqStr(htmlNumberStr(htmlCell(row, freqCol)), false, "Upstream Channel Frequency")
+		if err != nil {
+			return nil, withParseKey(err, usKey)
+		}
+		ch.SignalPowerDBMV, err = parseSignalPowerStr(
+			htmlNumberStr(htmlCell(row, powerCol)), false, "Upstream Channel Signal Power")
+		if err != nil {
+			return nil, withParseKey(err, usKey)
+		}
+		result = append(result, ch)
+	}
+	return result, nil
+}
+
+// Parses the log timestamp in any of the layouts used by the event log
+// pages. Entries logged before the time was established have a zero
+// timestamp.
+func parseHTMLLogTimestamp(str string) (time.Time, error) {
+	if strings.EqualFold(str, timeNotEstablished) {
+		return time.Time{}, nil
+	}
+	var err error
+	for _, layout := range htmlLogTimestampFormats {
+		var t time.Time
+		t, err = time.ParseInLocation(layout, str, time.Local)
+		if err == nil {
+			return t, nil
+		}
+	}
+	return time.Time{}, newParseError("Log Timestamp", str, err)
+}
+
+// Populates the log entries from the event log table.
+func populateHTMLLogEntries(tables []htmlTable) ([]LogEntry, error) {
+	const logKey = "Event Log"
+	rows, header := findHTMLTable(tables, []string{"time", "priority", "description"}, nil)
+	if header == nil {
+		return nil, &ParseError{
+			Desc: "Log list",
+			Key:  logKey,
+			Err:  errors.New("unable to find the event log table"),
+		}
+	}
+	var (
+		timeCol     = htmlColumn(header, "time")
+		priorityCol = htmlColumn(header, "priority")
+		descCol     = htmlColumn(header, "description")
+	)
+
+	var result []LogEntry
+	for _, row := range rows {
+		if len(row) < len(header) {
+			continue
+		}
+		var (
+			l   LogEntry
+			err error
+		)
+		l.Timestamp, err = parseHTMLLogTimestamp(htmlCell(row, timeCol))
+		if err != nil {
+			return nil, withParseKey(err, logKey)
+		}
+		l.Priority, err = parseLogPriorityStr(htmlCell(row, priorityCol))
+		if err != nil {
+			return nil, withParseKey(err, logKey)
+		}
+		l.Log = parseLogEntry(htmlCell(row, descCol))
+		l.Message, l.Attributes = parseLogAttributesStr(l.Log)
+		l.Event = parseLogEventStr(l.Message)
+		result = append(result, l)
+	}
+	return result, nil
+}
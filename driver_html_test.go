@@ -0,0 +1,285 @@
+package cablemodemutil
+
+import (
+	"context"
+	"encoding/base64"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+const testCredential = "a1b2c3d4"
+
+// Returns a test server serving the specified pages from testdata/html,
+// requiring the basic or the cookie based login as specified.
+func newHTMLTestServer(t *testing.T, auth htmlAuthScheme, pages map[string]string) *httptest.Server {
+	t.Helper()
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		file, ok := pages[req.URL.Path]
+		if !ok {
+			http.NotFound(w, req)
+			return
+		}
+		user, pass, hasBasicAuth := req.BasicAuth()
+		validBasicAuth := hasBasicAuth && user == testUsername && pass == testPassword
+
+		switch auth {
+		case htmlAuthBasic:
+			if !validBasicAuth {
+				w.Header().Set("WWW-Authenticate", `Basic realm="NETGEAR CM600"`)
+				w.WriteHeader(http.StatusUnauthorized)
+				return
+			}
+		case htmlAuthCookie:
+			if strings.HasPrefix(req.URL.RawQuery, "login_") {
+				want := base64.StdEncoding.EncodeToString([]byte(testUsername + ":" + testPassword))
+				if !validBasicAuth || req.URL.RawQuery != "login_"+want {
+					w.WriteHeader(http.StatusUnauthorized)
+					return
+				}
+				_, _ = w.Write([]byte(testCredential))
+				return
+			}
+			c, err := req.Cookie(credentialCookie)
+			if err != nil || c.Value != testCredential || req.URL.RawQuery != "ct_"+testCredential {
+				w.WriteHeader(http.StatusUnauthorized)
+				return
+			}
+		case htmlAuthNone:
+		}
+
+		data, err := os.ReadFile(filepath.Join("testdata", "html", file))
+		if err != nil {
+			t.Errorf("unable to read %q, reason: %s", file, err)
+			w.WriteHeader(http.StatusInternalServerError)
+			return
+		}
+		_, _ = w.Write(data)
+	}))
+}
+
+// Returns a retriever for the specified model connecting to the test server.
+func newHTMLTestRetriever(t *testing.T, srv *httptest.Server, model string, password string) *Retriever {
+	t.Helper()
+	r, err := NewRetriever(&RetrieverInput{
+		Host:          strings.TrimPrefix(srv.URL, "http://"),
+		Model:         model,
+		HTTPClient:    srv.Client(),
+		Username:      testUsername,
+		ClearPassword: password,
+	})
+	if err != nil {
+		t.Fatalf("NewRetriever() failed, reason: %s", err)
+	}
+	return r
+}
+
+func TestParseHTMLTables(t *testing.T) {
+	tests := []struct {
+		page string
+		want []htmlTable
+	}{
+		{
+			"<p>no tables</p>",
+			nil,
+		},
+		{
+			"<TABLE><TR><TH>A</TH><TD> b &amp;\n c </TD></TR><tr><td>1<td>2</table>",
+			[]htmlTable{{{"A", "b & c"}, {"1", "2"}}},
+		},
+		{
+			"<table><tr><td>outer<table><tr><td>inner</td></tr></table></td></tr></table>",
+			[]htmlTable{{{"inner"}}, {{"outer"}}},
+		},
+		{
+			"<script>var s = '<table><tr><td>x</td></tr></table>';</script><!-- <table> --><table><tr><td>y</td></tr></table>",
+			[]htmlTable{{{"y"}}},
+		},
+	}
+
+	for _, test := range tests {
+		got := parseHTMLTables(test.page)
+		if !reflect.DeepEqual(got, test.want) {
+			t.Errorf("%q: parseHTMLTables() = %q want: %q", test.page, got, test.want)
+		}
+	}
+}
+
+func TestJSTagValues(t *testing.T) {
+	tests := []struct {
+		page string
+		want []string
+	}{
+		{
+			`function InitUsTableTagValue() { var tagValueList = '1|1|Locked|&nbsp;|'; }`,
+			[]string{"1", "1", "Locked", ""},
+		},
+		{
+			"function InitUsTableTagValue()\n{\n  var tagValueList = \"0|\";\n}",
+			[]string{"0"},
+		},
+		{
+			// The list belongs to a different function.
+			`function InitUsTableTagValue() { return []; } function InitDsTableTagValue() { var tagValueList = '0|'; }`,
+			nil,
+		},
+		{
+			"<p>no scripts</p>",
+			nil,
+		},
+	}
+
+	for _, test := range tests {
+		got := jsTagValues(test.page, "InitUsTableTagValue")
+		if !reflect.DeepEqual(got, test.want) {
+			t.Errorf("%q: jsTagValues() = %q want: %q", test.page, got, test.want)
+		}
+	}
+}
+
+// nolint:funlen
+func TestHTMLDriverArris(t *testing.T) {
+	srv := newHTMLTestServer(t, htmlAuthCookie, map[string]string{
+		arrisConnectionStatusPage: "sb8200_cmconnectionstatus.html",
+		arrisSoftwareInfoPage:     "sb8200_cmswinfo.html",
+		arrisEventLogPage:         "sb8200_cmeventlog.html",
+	})
+	defer srv.Close()
+
+	r := newHTMLTestRetriever(t, srv, "SB8200", testPassword)
+	st, err := r.Status()
+	if err != nil {
+		t.Fatalf("Status() failed, reason: %s", err)
+	}
+
+	if st.Info.Model != "SB8200" || st.Info.SerialNumber != "1234567890ABCDEF" || st.Info.MACAddress != "AA:BB:CC:DD:EE:FF" {
+		t.Errorf("Info = %+v want: {Model:SB8200 SerialNumber:1234567890ABCDEF MACAddress:AA:BB:CC:DD:EE:FF}", st.Info)
+	}
+	wantSW := SoftwareStatus{
+		FirmwareVersion:   "AB01.01.009.32_051619_193.0A.NSH",
+		HDVersion:         "6",
+		DOCSISSpecVersion: "Docsis 3.1",
+	}
+	if st.Software != wantSW {
+		t.Errorf("Software = %+v want: %+v", st.Software, wantSW)
+	}
+	if st.Startup.Downstream.FrequencyHZ != 507000000 || !st.Startup.Downstream.Locked ||
+		!st.Startup.Boot.Status || !st.Startup.Connectivity.Operational ||
+		!st.Startup.Security.Enabled || st.Startup.Security.Comment != "BPI+" {
+		t.Errorf("Startup = %+v does not match the startup procedure table", st.Startup)
+	}
+	wantUpTime := 7*24*time.Hour + 20*time.Hour + 28*time.Minute + 17*time.Second
+	if st.Connection.UpTime != wantUpTime || !st.Connection.DOCSISNetworkAccessAllowed || !st.Connection.InternetConnected {
+		t.Errorf("Connection = {UpTime:%s DOCSISNetworkAccessAllowed:%t InternetConnected:%t} want: {UpTime:%s DOCSISNetworkAccessAllowed:true InternetConnected:true}",
+			st.Connection.UpTime, st.Connection.DOCSISNetworkAccessAllowed, st.Connection.InternetConnected, wantUpTime)
+	}
+
+	wantDS := []DownstreamChannelInfo{
+		{Locked: true, Type: ChannelTypeSCQAM, Modulation: "QAM256", ChannelID: 5, FrequencyHZ: 507000000, SignalPowerDBMV: 5.4, SignalSNRMERDB: 42.1},
+		{Locked: true, Type: ChannelTypeSCQAM, Modulation: "QAM256", ChannelID: 6, FrequencyHZ: 513000000, SignalPowerDBMV: 5.1, SignalSNRMERDB: 41.9, CorrectedErrors: 12, UncorrectedErrors: 3},
+		{Locked: true, Type: ChannelTypeUnknown, Modulation: "Other", ChannelID: 33, FrequencyHZ: 722000000, SignalPowerDBMV: 3.2, SignalSNRMERDB: 40.3, CorrectedErrors: 123456},
+	}
+	if !reflect.DeepEqual(st.Connection.Downstream.Channels, wantDS) {
+		t.Errorf("Downstream.Channels = %+v want: %+v", st.Connection.Downstream.Channels, wantDS)
+	}
+	wantUS := []UpstreamChannelInfo{
+		{Locked: true, Type: ChannelTypeSCQAM, Modulation: "SC-QAM Upstream", ChannelID: 2, WidthHZ: 6400000, FrequencyHZ: 36700000, SignalPowerDBMV: 44.0},
+		{Locked: true, Type: ChannelTypeOFDMA, Modulation: "OFDM Upstream", ChannelID: 9, WidthHZ: 17000000, FrequencyHZ: 42000000, SignalPowerDBMV: 40.5},
+	}
+	if !reflect.DeepEqual(st.Connection.Upstream.Channels, wantUS) {
+		t.Errorf("Upstream.Channels = %+v want: %+v", st.Connection.Upstream.Channels, wantUS)
+	}
+
+	if len(st.Logs) != 2 {
+		t.Fatalf("len(Logs) = %d want: 2", len(st.Logs))
+	}
+	if !st.Logs[0].Timestamp.IsZero() || st.Logs[0].Priority != LogPriorityCritical || st.Logs[0].Event != LogEventRangingNoResponse {
+		t.Errorf("Logs[0] = %+v want: {Timestamp:<zero> Priority:Critical Event:%s}", st.Logs[0], LogEventRangingNoResponse)
+	}
+	wantTimestamp := time.Date(2021, time.February, 9, 8, 12, 42, 0, time.Local)
+	if !st.Logs[1].Timestamp.Equal(wantTimestamp) || st.Logs[1].Priority != LogPriorityWarning {
+		t.Errorf("Logs[1] = {Timestamp:%s Priority:%s} want: {Timestamp:%s Priority:Warning}",
+			st.Logs[1].Timestamp, st.Logs[1].Priority, wantTimestamp)
+	}
+	if st.Connection.CMTSMACAddress != "00:11:22:33:44:66" {
+		t.Errorf("Connection.CMTSMACAddress = %q want: %q", st.Connection.CMTSMACAddress, "00:11:22:33:44:66")
+	}
+
+	r = newHTMLTestRetriever(t, srv, "SB8200", "wrong-password")
+	if err := r.Authenticate(context.Background()); !errors.Is(err, ErrAuthFailed) {
+		t.Errorf("Authenticate() error = %v want: ErrAuthFailed", err)
+	}
+}
+
+func TestHTMLDriverArrisOlderFirmware(t *testing.T) {
+	// The older firmware ignores the login query and serves the page,
+	// requiring either no login or the basic auth.
+	for _, auth := range []htmlAuthScheme{htmlAuthNone, htmlAuthBasic} {
+		srv := newHTMLTestServer(t, auth, map[string]string{
+			arrisConnectionStatusPage: "sb8200_cmconnectionstatus.html",
+			arrisSoftwareInfoPage:     "sb8200_cmswinfo.html",
+			arrisEventLogPage:         "sb8200_cmeventlog.html",
+		})
+		r := newHTMLTestRetriever(t, srv, "SB8200", testPassword)
+		st, err := r.Status()
+		srv.Close()
+		if err != nil {
+			t.Errorf("%d: Status() failed, reason: %s", auth, err)
+			continue
+		}
+		if st.Info.SerialNumber != "1234567890ABCDEF" || len(st.Connection.Downstream.Channels) != 3 {
+			t.Errorf("%d: Status() = {Info:%+v len(Downstream.Channels):%d} does not match the pages",
+				auth, st.Info, len(st.Connection.Downstream.Channels))
+		}
+	}
+}
+
+func TestHTMLDriverNetgear(t *testing.T) {
+	srv := newHTMLTestServer(t, htmlAuthBasic, map[string]string{
+		netgearDocsisStatusPage: "cm600_docsisstatus.htm",
+	})
+	defer srv.Close()
+
+	r := newHTMLTestRetriever(t, srv, "CM600", testPassword)
+	st, err := r.Status()
+	if err != nil {
+		t.Fatalf("Status() failed, reason: %s", err)
+	}
+	want := SectionStartup | SectionConnection | SectionDownstreamChannels | SectionUpstreamChannels
+	if st.Sections != want {
+		t.Errorf("Sections = %b want: %b", st.Sections, want)
+	}
+	if !st.Startup.Security.Enabled || st.Startup.Downstream.FrequencyHZ != 555000000 {
+		t.Errorf("Startup = %+v does not match the startup procedure table", st.Startup)
+	}
+	wantUpTime := 2*24*time.Hour + 3*time.Hour + 4*time.Minute + 5*time.Second
+	if st.Connection.UpTime != wantUpTime {
+		t.Errorf("Connection.UpTime = %s want: %s", st.Connection.UpTime, wantUpTime)
+	}
+
+	// The unused channel slots are skipped.
+	wantDS := []DownstreamChannelInfo{
+		{Locked: true, Type: ChannelTypeSCQAM, Modulation: "QAM256", ChannelID: 17, FrequencyHZ: 555000000, SignalPowerDBMV: -1.2, SignalSNRMERDB: 38.9, CorrectedErrors: 45, UncorrectedErrors: 2},
+		{Locked: true, Type: ChannelTypeSCQAM, Modulation: "QAM256", ChannelID: 18, FrequencyHZ: 561000000, SignalPowerDBMV: -1.0, SignalSNRMERDB: 39.0},
+	}
+	if !reflect.DeepEqual(st.Connection.Downstream.Channels, wantDS) {
+		t.Errorf("Downstream.Channels = %+v want: %+v", st.Connection.Downstream.Channels, wantDS)
+	}
+	wantUS := []UpstreamChannelInfo{
+		{Locked: true, Type: ChannelTypeSCQAM, Modulation: "ATDMA", ChannelID: 3, WidthHZ: 6400000, FrequencyHZ: 30600000, SignalPowerDBMV: 46.3},
+	}
+	if !reflect.DeepEqual(st.Connection.Upstream.Channels, wantUS) {
+		t.Errorf("Upstream.Channels = %+v want: %+v", st.Connection.Upstream.Channels, wantUS)
+	}
+
+	r = newHTMLTestRetriever(t, srv, "CM600", "wrong-password")
+	if _, err := r.Status(); !errors.Is(err, ErrAuthFailed) {
+		t.Errorf("Status() error = %v want: ErrAuthFailed", err)
+	}
+}
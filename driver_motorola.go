@@ -0,0 +1,529 @@
+package cablemodemutil
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+)
+
+const (
+	// Frequencies in the Motorola channel rows are in MHz.
+	motoFreqMultiplier = 1000000
+	// Log timestamps are in the format "DAY MON DATE YYYY HH:MM:SS".
+	motoLogTimestampFormat = "Mon Jan 2 2006 15:04:05"
+)
+
+// The status sub-commands queried using GetMultipleHNAPs from the Motorola
+// cable modems.
+// nolint:gochecknoglobals
+var motoStatusSubCommands = []string{
+	// Software version info along with device MAC addr / serial.
+	"GetMotoStatusSoftware",
+	// Uptime and short connection status.
+	"GetMotoStatusConnectionInfo",
+	// Internet connection status.
+	"GetHomeConnection",
+	// Detailed connection status.
+	"GetMotoStatusStartupSequence",
+	// Downstream channel info.
+	"GetMotoStatusDownstreamChannelInfo",
+	// Upstream channel info.
+	"GetMotoStatusUpstreamChannelInfo",
+	// Event log.
+	"GetMotoStatusLog",
+}
+
+// The sub-commands required for retrieving each of the status sections
+// supported by the Motorola cable modems.
+// nolint:gochecknoglobals
+var motoSectionSubCommands = map[StatusSection][]string{
+	SectionInfo:               {"GetMotoStatusSoftware"},
+	SectionSoftware:           {"GetMotoStatusSoftware"},
+	SectionStartup:            {"GetMotoStatusStartupSequence"},
+	SectionConnection:         {"GetMotoStatusConnectionInfo", "GetHomeConnection"},
+	SectionDownstreamChannels: {"GetMotoStatusDownstreamChannelInfo"},
+	SectionUpstreamChannels:   {"GetMotoStatusUpstreamChannelInfo"},
+	SectionLogs:               {"GetMotoStatusLog"},
+}
+
+// Returns the sub-commands required for retrieving the specified status
+// sections from the Motorola cable modems, in the same order as
+// motoStatusSubCommands.
+func motoStatusSubCommandsFor(sections StatusSection) []string {
+	required := make(map[string]bool)
+	for section, cmds := range motoSectionSubCommands {
+		if !sections.Has(section) {
+			continue
+		}
+		for _, cmd := range cmds {
+			required[cmd] = true
+		}
+	}
+
+	var result []string
+	for _, cmd := range motoStatusSubCommands {
+		if required[cmd] {
+			result = append(result, cmd)
+		}
+	}
+	return result
+}
+
+// Driver for the Motorola cable modems using HNAP1 (Eg. Motorola MB8600 and
+// MB8611). The login handshake is the same as the Arris cable modems, but
+// the status is queried using the GetMoto* actions.
+type motorolaDriver struct {
+	model   string
+	session *hnapSession
+}
+
+// Returns a factory for the Motorola HNAP1 cable modem drivers of the
+// specified model. The model is not reported by the cable modem in any of
+// the status responses.
+func newMotorolaDriverFactory(model string) DriverFactory {
+	return func(cfg *DriverConfig) Driver {
+		return &motorolaDriver{model: model, session: newHNAPSession(cfg)}
+	}
+}
+
+// Capabilities returns the capabilities of the driver.
+func (d *motorolaDriver) Capabilities() DriverCapabilities {
+	return DriverCapabilities{
+		Vendor: "Motorola",
+		Models: []string{"MB8600", "MB8611"},
+		Sections: SectionInfo |
+			SectionSoftware |
+			SectionStartup |
+			SectionConnection |
+			SectionDownstreamChannels |
+			SectionUpstreamChannels |
+			SectionLogs,
+		Actions: true,
+	}
+}
+
+// Authenticate logs in to the cable modem, discarding the current session if
+// any.
+func (d *motorolaDriver) Authenticate(ctx context.Context) error {
+	return d.session.authenticate(ctx)
+}
+
+// FetchRaw fetches the raw status for the specified sections from the cable
+// modem using a single GetMultipleHNAPs request.
+func (d *motorolaDriver) FetchRaw(ctx context.Context, sections StatusSection) (CableModemRawStatus, error) {
+	return d.session.queryRawStatus(ctx, motoStatusSubCommandsFor(sections))
+}
+
+// Parse parses the raw status into the structured status.
+// nolint:cyclop
+func (d *motorolaDriver) Parse(raw CableModemRawStatus, sections StatusSection) (*CableModemStatus, error) {
+	err := validateSubResponses(raw, motoStatusSubCommandsFor(sections))
+	if err != nil {
+		return nil, fmt.Errorf("invalid status response. reason: %w", err)
+	}
+
+	result := CableModemStatus{Sections: sections & d.Capabilities().Sections}
+	if sections.Has(SectionInfo) {
+		err = populateMotoDeviceInfo(raw, d.model, &result.Info)
+		if err != nil {
+			return nil, err
+		}
+	}
+	if sections.Has(SectionSoftware) {
+		err = populateMotoSoftwareStatus(raw, &result.Software)
+		if err != nil {
+			return nil, err
+		}
+	}
+	if sections.Has(SectionStartup) {
+		err = populateMotoStartupStatus(raw, &result.Startup)
+		if err != nil {
+			return nil, err
+		}
+	}
+	if sections.Has(SectionConnection) {
+		err = populateMotoConnectionSummary(raw, &result.Connection)
+		if err != nil {
+			return nil, err
+		}
+	}
+	if sections.Has(SectionDownstreamChannels) {
+		result.Connection.Downstream.Channels, err = populateMotoDownstreamChannels(raw)
+		if err != nil {
+			return nil, err
+		}
+	}
+	if sections.Has(SectionUpstreamChannels) {
+		result.Connection.Upstream.Channels, err = populateMotoUpstreamChannels(raw)
+		if err != nil {
+			return nil, err
+		}
+	}
+	if sections.Has(SectionLogs) {
+		result.Logs, err = populateMotoLogEntries(raw)
+		if err != nil {
+			return nil, err
+		}
+		result.LogEventCounts = countLogEvents(result.Logs)
+		populateCMTSInfo(result.Logs, &result.Connection)
+	}
+	return &result, nil
+}
+
+// Do sends the specified SOAP action containing the specified payload within
+// an authenticated session.
+func (d *motorolaDriver) Do(ctx context.Context, action string, payload map[string]string) (ActionResponse, error) {
+	return d.session.do(ctx, action, payload, true)
+}
+
+// GetMultiple queries the specified actions in a single GetMultipleHNAPs
+// request within an authenticated session.
+func (d *motorolaDriver) GetMultiple(ctx context.Context, actions ...string) (map[string]ActionResponse, error) {
+	return d.session.getMultiple(ctx, actions...)
+}
+
+// Populates Motorola cable modem device information.
+func populateMotoDeviceInfo(status CableModemRawStatus, model string, result *DeviceInfo) error {
+	sw, err := actionResp(status, "GetMotoStatusSoftware")
+	if err != nil {
+		return err
+	}
+
+	result.Model = model
+	result.SerialNumber, err = parseString(sw, "StatusSoftwareSerialNum", "Serial Number")
+	if err != nil {
+		return err
+	}
+	result.MACAddress, err = parseString(sw, "StatusSoftwareMac", "MAC Address")
+	if err != nil {
+		return err
+	}
+	return nil
+}
+
+// Populates Motorola cable modem software status.
+func populateMotoSoftwareStatus(status CableModemRawStatus, result *SoftwareStatus) error {
+	sw, err := actionResp(status, "GetMotoStatusSoftware")
+	if err != nil {
+		return err
+	}
+
+	result.FirmwareVersion, err = parseString(sw, "StatusSoftwareSfVer", "Firmware Version")
+	if err != nil {
+		return err
+	}
+	result.CertificateInstalled, err = parseBool(sw, "StatusSoftwareCertificate", "Installed", "Certificate Installed")
+	if err != nil {
+		return err
+	}
+	result.CustomerVersion, err = parseString(sw, "StatusSoftwareCustomerVer", "Customer Version")
+	if err != nil {
+		return err
+	}
+	result.HDVersion, err = parseString(sw, "StatusSoftwareHdVer", "HD Version")
+	if err != nil {
+		return err
+	}
+	result.DOCSISSpecVersion, err = parseString(sw, "StatusSoftwareSpecVer", "DOCSIS Spec Version")
+	if err != nil {
+		return err
+	}
+	return nil
+}
+
+// Populates Motorola cable modem startup status.
+// nolint:funlen
+func populateMotoStartupStatus(status CableModemRawStatus, result *StartupStatus) error {
+	startup, err := actionResp(status, "GetMotoStatusStartupSequence")
+	if err != nil {
+		return err
+	}
+
+	result.Boot.Status, err = parseBool(startup, "MotoConnBootStatus", "OK", "Boot Status")
+	if err != nil {
+		return err
+	}
+	result.Boot.Operational, err = parseBool(startup, "MotoConnBootComment", "Operational", "Boot Comment")
+	if err != nil {
+		return err
+	}
+	result.ConfigFile.Status, err = parseBool(
+		startup,
+		"MotoConnConfigurationFileStatus",
+		"OK",
+		"Configuration File Status",
+	)
+	if err != nil {
+		return err
+	}
+	result.ConfigFile.Comment, err = parseString(
+		startup,
+		"MotoConnConfigurationFileComment",
+		"Configuration File Comment",
+	)
+	if err != nil {
+		return err
+	}
+	result.Connectivity.Status, err = parseBool(startup, "MotoConnConnectivityStatus", "OK", "Connectivity Status")
+	if err != nil {
+		return err
+	}
+	result.Connectivity.Operational, err = parseBool(
+		startup,
+		"MotoConnConnectivityComment",
+		"Operational",
+		"Connectivity Comment",
+	)
+	if err != nil {
+		return err
+	}
+	result.Downstream.FrequencyHZ, err = parseFreq(startup, "MotoConnDSFreq", true, "Downstream Connection Frequency")
+	if err != nil {
+		return err
+	}
+	result.Downstream.Locked, err = parseBool(startup, "MotoConnDSComment", "Locked", "Downstream Connection Comment")
+	if err != nil {
+		return err
+	}
+	result.Security.Enabled, err = parseBool(startup, "MotoConnSecurityStatus", "Enabled", "Security Status")
+	if err != nil {
+		return err
+	}
+	result.Security.Comment, err = parseString(startup, "MotoConnSecurityComment", "Security Comment")
+	if err != nil {
+		return err
+	}
+	return nil
+}
+
+// Populates Motorola cable modem connection status excluding the channel
+// information. The system time and the downstream/upstream summary are not
+// reported by the Motorola cable modems.
+func populateMotoConnectionSummary(status CableModemRawStatus, result *ConnectionStatus) error {
+	conn, err := actionResp(status, "GetMotoStatusConnectionInfo")
+	if err != nil {
+		return err
+	}
+	home, err := actionResp(status, "GetHomeConnection")
+	if err != nil {
+		return err
+	}
+
+	result.UpTime, err = parseDuration(conn, "MotoConnSystemUpTime", "System Up Time")
+	if err != nil {
+		return err
+	}
+	result.DOCSISNetworkAccessAllowed, err = parseBool(
+		conn,
+		"MotoConnNetworkAccess",
+		"Allowed",
+		"DOCSIS Network Access",
+	)
+	if err != nil {
+		return err
+	}
+	result.InternetConnected, err = parseBool(home, "MotoHomeOnline", "Connected", "Internet Connection Status")
+	if err != nil {
+		return err
+	}
+	return nil
+}
+
+// Returns the columns in each of the rows in the specified squashed rows,
+// with the whitespace around the column values trimmed. The rows are
+// delimited by the specified delimiter and the columns by a '^'. Rows which
+// are blank are skipped.
+func motoRows(squashedRows string, delim string) [][]string {
+	var result [][]string
+	for _, row := range strings.Split(squashedRows, delim) {
+		if strings.TrimSpace(row) == "" {
+			continue
+		}
+		cols := strings.Split(row, "^")
+		for i := range cols {
+			cols[i] = strings.TrimSpace(cols[i])
+		}
+		result = append(result, cols)
+	}
+	return result
+}
+
+// Parses the specified string as a channel frequency in MHz.
+func parseMotoFreqStr(str string, desc string) (float32, error) {
+	freq, err := parseFloat32(str, false, "", desc)
+	if err != nil {
+		return 0, err
+	}
+	return freq * motoFreqMultiplier, nil
+}
+
+// Populates Motorola cable modem downstream channel information.
+func populateMotoDownstreamChannels(status CableModemRawStatus) ([]DownstreamChannelInfo, error) {
+	const dsKey = "MotoConnDownstreamChannel"
+	dsInfo, err := actionResp(status, "GetMotoStatusDownstreamChannelInfo")
+	if err != nil {
+		return nil, err
+	}
+	squashedRows, err := parseString(dsInfo, dsKey, "Downstream Channel info")
+	if err != nil {
+		return nil, err
+	}
+
+	// Each row is delimited by a '|+|'
+	rows := motoRows(squashedRows, "|+|")
+	result := make([]DownstreamChannelInfo, len(rows))
+	for i, cols := range rows {
+		// The columns are:
+		// Row ID, Lock Status, Modulation, Channel ID, Frequency (MHz), Power, SNR, Corrected Err, Uncorrected Err, Blank
+		if len(cols) != 10 {
+			return nil, &ParseError{
+				Desc:  "Downstream Channel info",
+				Key:   dsKey,
+				Value: strings.Join(cols, "^"),
+				Err:   fmt.Errorf("expected 10 columns in a downstream channel, actual %d", len(cols)),
+			}
+		}
+
+		result[i].Locked = strings.EqualFold(cols[1], "Locked")
+		result[i].Modulation = cols[2]
+		result[i].Type = parseChannelTypeStr(cols[2])
+		result[i].ChannelID, err = parseChannelIDStr(cols[3], "Downstream Channel ID")
+		if err != nil {
+			return nil, withParseKey(err, dsKey)
+		}
+		result[i].FrequencyHZ, err = parseMotoFreqStr(cols[4], "Downstream Channel Frequency")
+		if err != nil {
+			return nil, withParseKey(err, dsKey)
+		}
+		result[i].SignalPowerDBMV, err = parseSignalPowerStr(cols[5], false, "Downstream Channel Signal Power")
+		if err != nil {
+			return nil, withParseKey(err, dsKey)
+		}
+		result[i].SignalSNRMERDB, err = parseSignalSNRStr(cols[6], false, "Downstream Channel Signal SNR/MER")
+		if err != nil {
+			return nil, withParseKey(err, dsKey)
+		}
+		result[i].CorrectedErrors, err = parseSignalErrorsStr(cols[7], "Downstream Channel Signal Corrected Errors")
+		if err != nil {
+			return nil, withParseKey(err, dsKey)
+		}
+		result[i].UncorrectedErrors, err = parseSignalErrorsStr(cols[8], "Downstream Channel Signal Uncorrected Errors")
+		if err != nil {
+			return nil, withParseKey(err, dsKey)
+		}
+	}
+
+	return result, nil
+}
+
+// Populates Motorola cable modem upstream channel information.
+func populateMotoUpstreamChannels(status CableModemRawStatus) ([]UpstreamChannelInfo, error) {
+	const usKey = "MotoConnUpstreamChannel"
+	usInfo, err := actionResp(status, "GetMotoStatusUpstreamChannelInfo")
+	if err != nil {
+		return nil, err
+	}
+	squashedRows, err := parseString(usInfo, usKey, "Upstream Channel info")
+	if err != nil {
+		return nil, err
+	}
+
+	// Each row is delimited by a '|+|'
+	rows := motoRows(squashedRows, "|+|")
+	result := make([]UpstreamChannelInfo, len(rows))
+	for i, cols := range rows {
+		// The columns are:
+		// Row ID, Lock Status, Channel Type, Channel ID, Symbol Rate (Ksym/sec), Frequency (MHz), Power, Blank
+		if len(cols) != 8 {
+			return nil, &ParseError{
+				Desc:  "Upstream Channel info",
+				Key:   usKey,
+				Value: strings.Join(cols, "^"),
+				Err:   fmt.Errorf("expected 8 columns in an upstream channel, actual %d", len(cols)),
+			}
+		}
+
+		result[i].Locked = strings.EqualFold(cols[1], "Locked")
+		result[i].Modulation = cols[2]
+		result[i].Type = parseChannelTypeStr(cols[2])
+		result[i].ChannelID, err = parseChannelIDStr(cols[3], "Upstream Channel ID")
+		if err != nil {
+			return nil, withParseKey(err, usKey)
+		}
+		var symbolRate float32
+		symbolRate, err = parseFloat32(cols[4], false, "", "Upstream Channel Symbol Rate")
+		if err != nil {
+			return nil, withParseKey(err, usKey)
+		}
+		if result[i].Type == ChannelTypeSCQAM {
+			// The width is only derivable from the symbol rate for the
+			// SC-QAM channels.
+			result[i].WidthHZ = scQAMUpstreamWidth(symbolRate)
+		}
+		result[i].FrequencyHZ, err = parseMotoFreqStr(cols[5], "Upstream Channel Frequency")
+		if err != nil {
+			return nil, withParseKey(err, usKey)
+		}
+		result[i].SignalPowerDBMV, err = parseSignalPowerStr(cols[6], false, "Upstream Channel Signal Power")
+		if err != nil {
+			return nil, withParseKey(err, usKey)
+		}
+	}
+
+	return result, nil
+}
+
+// Parses the Motorola log timestamp from the specified date and time string
+// values.
+func parseMotoLogTimestamp(dateStr string, timeStr string) (time.Time, error) {
+	timestamp := fmt.Sprintf("%s %s", dateStr, timeStr)
+	t, err := time.ParseInLocation(motoLogTimestampFormat, timestamp, time.Local)
+	if err != nil {
+		return time.Time{}, newParseError("Log Timestamp", timestamp, err)
+	}
+	return t, nil
+}
+
+// Populates Motorola cable modem log entries.
+func populateMotoLogEntries(status CableModemRawStatus) ([]LogEntry, error) {
+	const logKey = "MotoStatusLogList"
+	logInfo, err := actionResp(status, "GetMotoStatusLog")
+	if err != nil {
+		return nil, err
+	}
+	squashedRows, err := parseString(logInfo, logKey, "Log list")
+	if err != nil {
+		return nil, err
+	}
+
+	// Each row is delimited by a '}-{'
+	rows := motoRows(squashedRows, "}-{")
+	result := make([]LogEntry, len(rows))
+	for i, cols := range rows {
+		// The columns are:
+		// Time, Date, Priority, Log
+		if len(cols) != 4 {
+			return nil, &ParseError{
+				Desc:  "Log list",
+				Key:   logKey,
+				Value: strings.Join(cols, "^"),
+				Err:   fmt.Errorf("expected 4 columns in a log entry, actual %d", len(cols)),
+			}
+		}
+
+		result[i].Timestamp, err = parseMotoLogTimestamp(cols[1], cols[0])
+		if err != nil {
+			return nil, withParseKey(err, logKey)
+		}
+		result[i].Priority, err = parseLogPriorityStr(cols[2])
+		if err != nil {
+			return nil, withParseKey(err, logKey)
+		}
+		result[i].Log = parseLogEntry(cols[3])
+		result[i].Message, result[i].Attributes = parseLogAttributesStr(result[i].Log)
+		result[i].Event = parseLogEventStr(result[i].Message)
+	}
+
+	return result, nil
+}
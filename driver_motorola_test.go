@@ -0,0 +1,116 @@
+package cablemodemutil
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestMotorolaDriverGoldenFixture(t *testing.T) {
+	f, err := LoadFixture("testdata/golden/mb8611.json")
+	if err != nil {
+		t.Fatalf("LoadFixture() failed, reason: %s", err)
+	}
+	st, err := newReplayRetriever(f).Status()
+	if err != nil {
+		t.Fatalf("Status() failed, reason: %s", err)
+	}
+
+	if st.Info.Model != "MB8611" {
+		t.Errorf("Info.Model = %q want: %q", st.Info.Model, "MB8611")
+	}
+	if st.Software.FirmwareVersion != "8611-19.2.18" {
+		t.Errorf("Software.FirmwareVersion = %q want: %q", st.Software.FirmwareVersion, "8611-19.2.18")
+	}
+	if st.Startup.Downstream.FrequencyHZ != 483000000 || !st.Startup.Downstream.Locked {
+		t.Errorf("Startup.Downstream = %+v want: {FrequencyHZ:4.83e+08 Locked:true}", st.Startup.Downstream)
+	}
+	wantUpTime := 6*24*time.Hour + 4*time.Hour + 31*time.Minute + 17*time.Second
+	if st.Connection.UpTime != wantUpTime || !st.Connection.InternetConnected || !st.Connection.DOCSISNetworkAccessAllowed {
+		t.Errorf("Connection = {UpTime:%s InternetConnected:%t DOCSISNetworkAccessAllowed:%t} want: {UpTime:%s InternetConnected:true DOCSISNetworkAccessAllowed:true}",
+			st.Connection.UpTime, st.Connection.InternetConnected, st.Connection.DOCSISNetworkAccessAllowed, wantUpTime)
+	}
+
+	wantDS := []DownstreamChannelInfo{
+		{true, ChannelTypeSCQAM, "QAM256", 9, 483000000, 3.2, 41.1, 15, 0},
+		{true, ChannelTypeSCQAM, "QAM256", 10, 489000000, 3.0, 40.9, 7, 1},
+		{true, ChannelTypeSCQAM, "QAM256", 11, 495000000, 2.8, 40.8, 0, 0},
+		{true, ChannelTypeOFDM, "OFDM PLC", 33, 690000000, 1.9, 39.5, 1432, 0},
+	}
+	if len(st.Connection.Downstream.Channels) != len(wantDS) {
+		t.Fatalf("len(Downstream.Channels) = %d want: %d", len(st.Connection.Downstream.Channels), len(wantDS))
+	}
+	for i, want := range wantDS {
+		if got := st.Connection.Downstream.Channels[i]; got != want {
+			t.Errorf("Downstream.Channels[%d] = %+v want: %+v", i, got, want)
+		}
+	}
+
+	wantUS := []UpstreamChannelInfo{
+		{true, ChannelTypeSCQAM, "SC-QAM", 1, 6400000, 35600000, 44.8},
+		{true, ChannelTypeSCQAM, "SC-QAM", 2, 6400000, 29200000, 45.0},
+		{true, ChannelTypeOFDMA, "OFDMA", 41, 0, 39800000, 41.3},
+	}
+	if len(st.Connection.Upstream.Channels) != len(wantUS) {
+		t.Fatalf("len(Upstream.Channels) = %d want: %d", len(st.Connection.Upstream.Channels), len(wantUS))
+	}
+	for i, want := range wantUS {
+		if got := st.Connection.Upstream.Channels[i]; got != want {
+			t.Errorf("Upstream.Channels[%d] = %+v want: %+v", i, got, want)
+		}
+	}
+
+	if len(st.Logs) != 2 {
+		t.Fatalf("len(Logs) = %d want: 2", len(st.Logs))
+	}
+	wantTimestamp := time.Date(2021, time.February, 9, 8, 12, 42, 0, time.Local)
+	if !st.Logs[0].Timestamp.Equal(wantTimestamp) {
+		t.Errorf("Logs[0].Timestamp = %s want: %s", st.Logs[0].Timestamp, wantTimestamp)
+	}
+	if st.Logs[0].Priority != LogPriorityCritical || st.Logs[0].Event != LogEventRangingNoResponse {
+		t.Errorf("Logs[0] = {Priority:%s Event:%s} want: {Priority:Critical Event:%s}",
+			st.Logs[0].Priority, st.Logs[0].Event, LogEventRangingNoResponse)
+	}
+	if st.Logs[1].Priority != LogPriorityNotice {
+		t.Errorf("Logs[1].Priority = %s want: Notice", st.Logs[1].Priority)
+	}
+	if st.Connection.CMTSMACAddress != "00:01:5c:7a:3c:46" {
+		t.Errorf("Connection.CMTSMACAddress = %q want: %q", st.Connection.CMTSMACAddress, "00:01:5c:7a:3c:46")
+	}
+}
+
+func TestMotorolaDriverInvalidRows(t *testing.T) {
+	tests := []struct {
+		cmd  string
+		key  string
+		rows string
+	}{
+		{"GetMotoStatusDownstreamChannelInfo", "MotoConnDownstreamChannel", "1^Locked^QAM256^9^483.0^3.2^"},
+		{"GetMotoStatusDownstreamChannelInfo", "MotoConnDownstreamChannel", "1^Locked^QAM256^9^483 MHz^3.2^41.1^15^0^"},
+		{"GetMotoStatusUpstreamChannelInfo", "MotoConnUpstreamChannel", "1^Locked^SC-QAM^1^5120^35.6^"},
+		{"GetMotoStatusLog", "MotoStatusLogList", "08:12:42^Tue Feb 09 2021^Critical^T3 time-out"},
+		{"GetMotoStatusLog", "MotoStatusLogList", "08:12:42^09/02/2021^Critical (3)^T3 time-out"},
+	}
+
+	d := newMotorolaDriverFactory("MB8611")(&DriverConfig{})
+	for _, test := range tests {
+		raw := CableModemRawStatus{
+			actionResponseKey(test.cmd): map[string]interface{}{
+				actionResultKey(test.cmd): "OK",
+				test.key:                  test.rows,
+			},
+		}
+		var sections StatusSection
+		for section, cmds := range motoSectionSubCommands {
+			if cmds[0] == test.cmd {
+				sections = section
+			}
+		}
+
+		_, err := d.Parse(raw, sections)
+		var pErr *ParseError
+		if !errors.As(err, &pErr) || pErr.Key != test.key {
+			t.Errorf("%q: Parse() error = %v want: ParseError for key %q", test.rows, err, test.key)
+		}
+	}
+}
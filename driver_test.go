@@ -0,0 +1,104 @@
+package cablemodemutil
+
+import (
+	"context"
+	"errors"
+	"testing"
+)
+
+// A driver serving a fixed status, implementing neither ActionDriver nor
+// ConfigurationDriver.
+type fakeDriver struct {
+	cfg *DriverConfig
+}
+
+func (d *fakeDriver) Capabilities() DriverCapabilities {
+	return DriverCapabilities{Vendor: "Fake", Models: []string{"FAKE1"}, Sections: SectionInfo}
+}
+
+func (d *fakeDriver) Authenticate(ctx context.Context) error {
+	return nil
+}
+
+func (d *fakeDriver) FetchRaw(ctx context.Context, sections StatusSection) (CableModemRawStatus, error) {
+	return CableModemRawStatus{"model": "FAKE1", "host": d.cfg.Host}, nil
+}
+
+func (d *fakeDriver) Parse(raw CableModemRawStatus, sections StatusSection) (*CableModemStatus, error) {
+	st := CableModemStatus{Sections: sections}
+	st.Info.Model = raw["model"].(string)
+	return &st, nil
+}
+
+func TestRegisterDriver(t *testing.T) {
+	RegisterDriver("fake1", func(cfg *DriverConfig) Driver { return &fakeDriver{cfg: cfg} })
+	defer func() {
+		driversMu.Lock()
+		delete(drivers, "FAKE1")
+		driversMu.Unlock()
+	}()
+
+	found := false
+	for _, m := range RegisteredModels() {
+		found = found || m == "FAKE1"
+	}
+	if !found {
+		t.Errorf("RegisteredModels() = %q missing the registered model FAKE1", RegisteredModels())
+	}
+
+	r, err := NewRetriever(&RetrieverInput{Host: "192.168.100.1", Model: "Fake1"})
+	if err != nil {
+		t.Fatalf("NewRetriever() failed, reason: %s", err)
+	}
+	st, err := r.Status()
+	if err != nil {
+		t.Fatalf("Status() failed, reason: %s", err)
+	}
+	if st.Info.Model != "FAKE1" {
+		t.Errorf("Status() Info.Model = %q want: %q", st.Info.Model, "FAKE1")
+	}
+	// Only the sections supported by the driver are requested.
+	if st.Sections != SectionInfo {
+		t.Errorf("Status() Sections = %b want: %b", st.Sections, SectionInfo)
+	}
+
+	_, err = r.Do(context.Background(), "GetArrisRegisterInfo", nil)
+	if !errors.Is(err, ErrUnsupported) {
+		t.Errorf("Do() error = %v want: ErrUnsupported", err)
+	}
+	err = r.Reboot(context.Background())
+	if !errors.Is(err, ErrUnsupported) {
+		t.Errorf("Reboot() error = %v want: ErrUnsupported", err)
+	}
+}
+
+func TestNewRetrieverUnknownModel(t *testing.T) {
+	input := RetrieverInput{Host: "192.168.100.1", Model: "UNKNOWN"}
+	_, err := NewRetriever(&input)
+	if !errors.Is(err, ErrUnsupported) {
+		t.Errorf("NewRetriever() error = %v want: ErrUnsupported", err)
+	}
+
+	_, err = NewStatusRetriever(&input).Status()
+	if !errors.Is(err, ErrUnsupported) {
+		t.Errorf("Status() error = %v want: ErrUnsupported", err)
+	}
+}
+
+func TestRetrieverAuthenticate(t *testing.T) {
+	r, sim := newTestRetriever(t, testPassword)
+	if err := r.Authenticate(context.Background()); err != nil {
+		t.Fatalf("Authenticate() failed, reason: %s", err)
+	}
+	if _, err := r.Status(); err != nil {
+		t.Fatalf("Status() failed, reason: %s", err)
+	}
+	if got := sim.Logins(); got != 1 {
+		t.Errorf("Logins() = %d want: 1", got)
+	}
+
+	r, _ = newTestRetriever(t, "wrong-password")
+	if err := r.Authenticate(context.Background()); !errors.Is(err, ErrAuthFailed) {
+		t.Errorf("Authenticate() error = %v want: ErrAuthFailed", err)
+	}
+}
@@ -0,0 +1,198 @@
+package cablemodemutil
+
+import (
+	"errors"
+	"fmt"
+)
+
+var (
+	// ErrAuthFailed is matched by errors returned when the cable modem
+	// rejects the supplied credentials.
+	ErrAuthFailed = errors.New("authentication rejected by the cable modem")
+	// ErrSessionExpired is matched by errors returned when the authenticated
+	// session with the cable modem is no longer valid, and logging in again
+	// is expected to resolve the failure.
+	ErrSessionExpired = errors.New("session with the cable modem has expired")
+	// ErrMalformedResponse is matched by errors returned when the response
+	// from the cable modem does not follow the expected SOAP envelope format.
+	ErrMalformedResponse = errors.New("malformed response from the cable modem")
+	// ErrResultNotOK is matched by errors returned when the result of an
+	// action in the response from the cable modem is not "OK".
+	ErrResultNotOK = errors.New("action result from the cable modem is not OK")
+	// ErrParse is matched by errors returned when a field in the status
+	// response cannot be parsed, possibly due to a change in the format
+	// used by the cable modem firmware.
+	ErrParse = errors.New("unable to parse field in the cable modem status")
+	// ErrUnsupported is matched by errors returned when the cable modem
+	// model or the requested operation is not supported by the driver.
+	ErrUnsupported = errors.New("unsupported by the cable modem driver")
+)
+
+// AuthError is returned when authentication with the cable modem fails
+// because the credentials were rejected.
+type AuthError struct {
+	// User name used for the authentication attempt.
+	Username string
+	// The underlying error.
+	Err error
+}
+
+func (e *AuthError) Error() string {
+	return fmt.Sprintf("auth failed for user %q.\nreason: %s", e.Username, e.Err)
+}
+
+// Unwrap returns the underlying error.
+func (e *AuthError) Unwrap() error {
+	return e.Err
+}
+
+// Is returns true if the target is ErrAuthFailed.
+func (e *AuthError) Is(target error) bool {
+	return target == ErrAuthFailed
+}
+
+// HTTPStatusError is returned when the cable modem responds to a SOAP
+// action request with a non-success HTTP status code.
+type HTTPStatusError struct {
+	// SOAP action of the request.
+	Action string
+	// HTTP status code in the response.
+	StatusCode int
+	// Body of the response.
+	Body string
+}
+
+func (e *HTTPStatusError) Error() string {
+	if e.sessionExpired() {
+		return fmt.Sprintf(
+			"HTTP POST request for SOAP action %q failed with 404 "+
+				"status code possibly due to credentials having expired.\n"+
+				"body:%s",
+			e.Action,
+			e.Body,
+		)
+	}
+	return fmt.Sprintf(
+		"HTTP POST request for SOAP action %q failed due to non-success "+
+			"status code: %d\nbody:%s",
+		e.Action,
+		e.StatusCode,
+		e.Body,
+	)
+}
+
+// Is returns true if the target is ErrSessionExpired and the status code
+// indicates the credentials having expired.
+func (e *HTTPStatusError) Is(target error) bool {
+	return target == ErrSessionExpired && e.sessionExpired()
+}
+
+// The cable modem responds with a 404 status code for authenticated actions
+// when the credentials have expired.
+func (e *HTTPStatusError) sessionExpired() bool {
+	return e.StatusCode == 404 && e.Action != loginAction && e.Action != deviceSettingsAction
+}
+
+// ResponseError is returned when the response from the cable modem does not
+// follow the expected SOAP envelope format.
+type ResponseError struct {
+	// SOAP action of the request.
+	Action string
+	// Reason describing what is malformed in the response.
+	Reason string
+	// The response (or the relevant part of it).
+	Response string
+	// The underlying error if any.
+	Err error
+}
+
+func (e *ResponseError) Error() string {
+	if e.Err != nil {
+		return fmt.Sprintf("action: %s, %s, reason: %s", e.Action, e.Reason, e.Err)
+	}
+	return fmt.Sprintf("action: %s, %s.\nresponse: %s", e.Action, e.Reason, e.Response)
+}
+
+// Unwrap returns the underlying error.
+func (e *ResponseError) Unwrap() error {
+	return e.Err
+}
+
+// Is returns true if the target is ErrMalformedResponse.
+func (e *ResponseError) Is(target error) bool {
+	return target == ErrMalformedResponse
+}
+
+// ResultError is returned when the result of a SOAP action (or a
+// sub-command within a GetMultipleHNAPs request) is not "OK".
+type ResultError struct {
+	// SOAP action or sub-command name.
+	Action string
+	// Result reported by the cable modem.
+	Result string
+	// The unpacked response.
+	Response string
+}
+
+func (e *ResultError) Error() string {
+	return fmt.Sprintf(
+		"action: %s, result in unpacked response is %q, expected \"OK\".\nunpacked response: %s",
+		e.Action,
+		e.Result,
+		e.Response,
+	)
+}
+
+// Is returns true if the target is ErrResultNotOK.
+func (e *ResultError) Is(target error) bool {
+	return target == ErrResultNotOK
+}
+
+// ParseError is returned when a field in the status response from the cable
+// modem cannot be parsed.
+type ParseError struct {
+	// Description of the field being parsed.
+	Desc string
+	// Key containing the field in the status response, if known.
+	Key string
+	// Raw value that failed to parse.
+	Value string
+	// The underlying error.
+	Err error
+}
+
+func (e *ParseError) Error() string {
+	if e.Key == "" {
+		return fmt.Sprintf("parsing %q, unable to parse %q: %s", e.Desc, e.Value, e.Err)
+	}
+	return fmt.Sprintf("parsing %q (key %q), unable to parse %q: %s", e.Desc, e.Key, e.Value, e.Err)
+}
+
+// Unwrap returns the underlying error.
+func (e *ParseError) Unwrap() error {
+	return e.Err
+}
+
+// Is returns true if the target is ErrParse.
+func (e *ParseError) Is(target error) bool {
+	return target == ErrParse
+}
+
+// Returns a new parse error for the specified field description and value.
+func newParseError(desc string, value string, err error) *ParseError {
+	return &ParseError{
+		Desc:  desc,
+		Value: value,
+		Err:   err,
+	}
+}
+
+// Annotates the specified error with the key being parsed if it is a parse
+// error without a key.
+func withParseKey(err error, key string) error {
+	var pErr *ParseError
+	if errors.As(err, &pErr) && pErr.Key == "" {
+		pErr.Key = key
+	}
+	return err
+}
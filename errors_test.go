@@ -0,0 +1,79 @@
+package cablemodemutil
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+)
+
+var errorsIsTests = []struct {
+	name   string
+	err    error
+	target error
+	want   bool
+}{
+	{
+		name:   "404 for query action is a session expiry",
+		err:    &HTTPStatusError{Action: queryAction, StatusCode: 404},
+		target: ErrSessionExpired,
+		want:   true,
+	},
+	{
+		name:   "404 for login action is not a session expiry",
+		err:    &HTTPStatusError{Action: loginAction, StatusCode: 404},
+		target: ErrSessionExpired,
+		want:   false,
+	},
+	{
+		name:   "500 for query action is not a session expiry",
+		err:    &HTTPStatusError{Action: queryAction, StatusCode: 500},
+		target: ErrSessionExpired,
+		want:   false,
+	},
+	{
+		name:   "Wrapped auth error",
+		err:    fmt.Errorf("wrapped: %w", &AuthError{Username: "admin", Err: &ResultError{Action: loginAction}}),
+		target: ErrAuthFailed,
+		want:   true,
+	},
+	{
+		name:   "Auth error wrapping a result error",
+		err:    &AuthError{Username: "admin", Err: &ResultError{Action: loginAction}},
+		target: ErrResultNotOK,
+		want:   true,
+	},
+	{
+		name:   "Response error",
+		err:    &ResponseError{Action: queryAction, Reason: "reason"},
+		target: ErrMalformedResponse,
+		want:   true,
+	},
+	{
+		name:   "Parse error",
+		err:    withParseKey(newParseError("desc", "value", errors.New("reason")), "key"),
+		target: ErrParse,
+		want:   true,
+	},
+}
+
+func TestErrorsIs(t *testing.T) {
+	for _, tc := range errorsIsTests {
+		if got := errors.Is(tc.err, tc.target); got != tc.want {
+			t.Errorf("%q: errors.Is(%q, %q) = %t want: %t", tc.name, tc.err, tc.target, got, tc.want)
+		}
+	}
+}
+
+func TestParseErrorKeyAndValue(t *testing.T) {
+	data := actionResponseBody{"Freq": "123 MHz"}
+	_, err := parseFreq(data, "Freq", true, "Frequency")
+
+	var pErr *ParseError
+	if !errors.As(err, &pErr) {
+		t.Fatalf("parseFreq() error = %v, want a *ParseError", err)
+	}
+	if pErr.Key != "Freq" || pErr.Value != "123 MHz" {
+		t.Errorf("parseFreq() ParseError{Key: %q, Value: %q} want: {Key: %q, Value: %q}",
+			pErr.Key, pErr.Value, "Freq", "123 MHz")
+	}
+}
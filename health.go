@@ -0,0 +1,303 @@
+package cablemodemutil
+
+import (
+	"fmt"
+	"strings"
+)
+
+// HealthRating identifies how healthy a channel or the cable modem is.
+type HealthRating uint8
+
+const (
+	// HealthGood indicates all the signal levels being within spec.
+	HealthGood HealthRating = iota
+	// HealthMarginal indicates signal levels close to the limits of the spec.
+	HealthMarginal
+	// HealthBad indicates signal levels out of spec, or channels without
+	// lock.
+	HealthBad
+)
+
+// String returns the string representation of the health rating.
+func (h HealthRating) String() string {
+	switch h {
+	case HealthGood:
+		return "Good"
+	case HealthMarginal:
+		return "Marginal"
+	case HealthBad:
+		return "Bad"
+	}
+	return "Unknown"
+}
+
+// SignalRange is an acceptable range for a signal level. Levels outside the
+// range are rated bad, and levels within the margin of either limit are rated
+// marginal.
+type SignalRange struct {
+	// Lower limit of the range.
+	Min float32
+	// Upper limit of the range.
+	Max float32
+	// Distance from either limit within which the level is rated marginal.
+	Margin float32
+}
+
+// Returns the rating for the specified level along with the reason if not
+// good.
+func (r *SignalRange) rate(level float32, desc string, unit string) (HealthRating, string) {
+	switch {
+	case level < r.Min:
+		return HealthBad, fmt.Sprintf("%s %.1f %s below minimum %.1f %s", desc, level, unit, r.Min, unit)
+	case level > r.Max:
+		return HealthBad, fmt.Sprintf("%s %.1f %s above maximum %.1f %s", desc, level, unit, r.Max, unit)
+	case level < r.Min+r.Margin:
+		return HealthMarginal, fmt.Sprintf("%s %.1f %s close to minimum %.1f %s", desc, level, unit, r.Min, unit)
+	case level > r.Max-r.Margin:
+		return HealthMarginal, fmt.Sprintf("%s %.1f %s close to maximum %.1f %s", desc, level, unit, r.Max, unit)
+	}
+	return HealthGood, ""
+}
+
+// HealthThresholds contains the thresholds used to evaluate the health of
+// the channels.
+type HealthThresholds struct {
+	// Acceptable range for the downstream signal power in dBmV.
+	DownstreamPower SignalRange
+	// Acceptable range for the downstream SNR/MER in dB keyed by the
+	// modulation (Eg. "QAM256"), or by the channel type (Eg. "OFDM") for
+	// the channels whose modulation is not present.
+	DownstreamSNR map[string]SignalRange
+	// Acceptable range for the downstream SNR/MER in dB for the channels
+	// whose modulation and channel type are not present in DownstreamSNR.
+	DefaultDownstreamSNR SignalRange
+	// Acceptable range for the upstream signal power in dBmV.
+	UpstreamPower SignalRange
+}
+
+// DefaultHealthThresholds returns the default thresholds, based on the
+// commonly recommended DOCSIS signal levels.
+func DefaultHealthThresholds() HealthThresholds {
+	// The SNR/MER has no upper limit.
+	const maxSNR = 100
+	return HealthThresholds{
+		DownstreamPower: SignalRange{Min: -15, Max: 15, Margin: 8},
+		DownstreamSNR: map[string]SignalRange{
+			"QAM64":                   {Min: 27, Max: maxSNR, Margin: 3},
+			"QAM256":                  {Min: 33, Max: maxSNR, Margin: 3},
+			"QAM1024":                 {Min: 35, Max: maxSNR, Margin: 3},
+			ChannelTypeOFDM.String():  {Min: 35, Max: maxSNR, Margin: 3},
+			ChannelTypeSCQAM.String(): {Min: 33, Max: maxSNR, Margin: 3},
+		},
+		DefaultDownstreamSNR: SignalRange{Min: 33, Max: maxSNR, Margin: 3},
+		UpstreamPower:        SignalRange{Min: 35, Max: 51, Margin: 3},
+	}
+}
+
+// ChannelHealth contains the health of a channel.
+type ChannelHealth struct {
+	// Direction of the channel.
+	Direction ChannelDirection
+	// Channel ID.
+	ChannelID uint32
+	// Frequency of the channel.
+	FrequencyHZ float32
+	// Modulation of the channel.
+	Modulation string
+	// Health rating of the channel.
+	Rating HealthRating
+	// Reasons for the rating if not good.
+	Reasons []string
+}
+
+// Returns the description of the channel.
+func (c *ChannelHealth) desc() string {
+	return fmt.Sprintf("%s channel %d (%s, %.0f Hz)", strings.ToLower(c.Direction.String()), c.ChannelID,
+		c.Modulation, c.FrequencyHZ)
+}
+
+// Updates the rating with the specified rating and reason if worse than the
+// current rating.
+func (c *ChannelHealth) update(rating HealthRating, reason string) {
+	if rating == HealthGood {
+		return
+	}
+	if rating > c.Rating {
+		c.Rating = rating
+	}
+	c.Reasons = append(c.Reasons, reason)
+}
+
+// Health contains the overall health of the cable modem.
+type Health struct {
+	// Overall health rating, which is the worst among the channels and the
+	// connectivity.
+	Rating HealthRating
+	// Reasons for the rating if not good, explaining which channels are out
+	// of spec.
+	Reasons []string
+	// Health of the downstream channels, the DOCSIS 3.1 OFDM downstream
+	// channels, the upstream channels and the DOCSIS 3.1 OFDMA upstream
+	// channels in that order.
+	Channels []ChannelHealth
+}
+
+// HealthEvaluator evaluates the health of the cable modem from its status.
+type HealthEvaluator struct {
+	thresholds HealthThresholds
+}
+
+// NewHealthEvaluator returns a new health evaluator using the specified
+// thresholds, or the default thresholds if nil.
+func NewHealthEvaluator(thresholds *HealthThresholds) *HealthEvaluator {
+	e := HealthEvaluator{}
+	if thresholds != nil {
+		e.thresholds = *thresholds
+	} else {
+		e.thresholds = DefaultHealthThresholds()
+	}
+	return &e
+}
+
+// Evaluate evaluates the health of all the channels in the status and rolls
+// them up into the overall health of the cable modem. The OFDM and OFDMA
+// channels also present in the OFDM and OFDMA channel lists are evaluated
+// only once, using the detailed information in the latter.
+func (e *HealthEvaluator) Evaluate(st *CableModemStatus) *Health {
+	result := Health{}
+	ds := &st.Connection.Downstream
+	ofdmIDs := make(map[uint32]bool, len(ds.OFDMChannels))
+	for i := range ds.OFDMChannels {
+		ofdmIDs[ds.OFDMChannels[i].ChannelID] = true
+	}
+	for i := range ds.Channels {
+		if ds.Channels[i].Type == ChannelTypeOFDM && ofdmIDs[ds.Channels[i].ChannelID] {
+			continue
+		}
+		result.Channels = append(result.Channels, e.EvaluateDownstreamChannel(&ds.Channels[i]))
+	}
+	for i := range ds.OFDMChannels {
+		result.Channels = append(result.Channels, e.EvaluateOFDMDownstreamChannel(&ds.OFDMChannels[i]))
+	}
+	us := &st.Connection.Upstream
+	ofdmaIDs := make(map[uint32]bool, len(us.OFDMAChannels))
+	for i := range us.OFDMAChannels {
+		ofdmaIDs[us.OFDMAChannels[i].ChannelID] = true
+	}
+	for i := range us.Channels {
+		if us.Channels[i].Type == ChannelTypeOFDMA && ofdmaIDs[us.Channels[i].ChannelID] {
+			continue
+		}
+		result.Channels = append(result.Channels, e.EvaluateUpstreamChannel(&us.Channels[i]))
+	}
+	for i := range us.OFDMAChannels {
+		result.Channels = append(result.Channels, e.EvaluateOFDMAUpstreamChannel(&us.OFDMAChannels[i]))
+	}
+
+	update := func(rating HealthRating, reason string) {
+		if rating > result.Rating {
+			result.Rating = rating
+		}
+		result.Reasons = append(result.Reasons, reason)
+	}
+	if st.Sections.Has(SectionConnection) && !st.Connection.InternetConnected {
+		update(HealthBad, "internet not connected")
+	}
+	if st.Sections.Has(SectionDownstreamChannels) && len(st.Connection.Downstream.Channels) == 0 {
+		update(HealthBad, "no downstream channels")
+	}
+	if st.Sections.Has(SectionUpstreamChannels) && len(st.Connection.Upstream.Channels) == 0 {
+		update(HealthBad, "no upstream channels")
+	}
+	for i := range result.Channels {
+		c := &result.Channels[i]
+		for _, reason := range c.Reasons {
+			update(c.Rating, c.desc()+": "+reason)
+		}
+	}
+	return &result
+}
+
+// EvaluateDownstreamChannel evaluates the health of the downstream channel.
+func (e *HealthEvaluator) EvaluateDownstreamChannel(c *DownstreamChannelInfo) ChannelHealth {
+	result := ChannelHealth{
+		Direction:   ChannelDownstream,
+		ChannelID:   c.ChannelID,
+		FrequencyHZ: c.FrequencyHZ,
+		Modulation:  c.Modulation,
+	}
+	if !c.Locked {
+		result.update(HealthBad, "not locked")
+		return result
+	}
+
+	result.update(e.thresholds.DownstreamPower.rate(c.SignalPowerDBMV, "power", "dBmV"))
+	snr, ok := e.thresholds.DownstreamSNR[c.Modulation]
+	if !ok {
+		snr, ok = e.thresholds.DownstreamSNR[c.Type.String()]
+	}
+	if !ok {
+		snr = e.thresholds.DefaultDownstreamSNR
+	}
+	result.update(snr.rate(c.SignalSNRMERDB, "SNR/MER", "dB"))
+	return result
+}
+
+// EvaluateOFDMDownstreamChannel evaluates the health of the DOCSIS 3.1 OFDM
+// downstream channel, rating the MER of the data subcarriers using the
+// SNR/MER threshold for the OFDM channel type.
+func (e *HealthEvaluator) EvaluateOFDMDownstreamChannel(c *OFDMDownstreamChannelInfo) ChannelHealth {
+	result := ChannelHealth{
+		Direction:   ChannelDownstream,
+		ChannelID:   c.ChannelID,
+		FrequencyHZ: c.PLCFrequencyHZ,
+		Modulation:  ChannelTypeOFDM.String(),
+	}
+	if !c.Locked {
+		result.update(HealthBad, "not locked")
+		return result
+	}
+
+	result.update(e.thresholds.DownstreamPower.rate(c.SignalPowerDBMV, "power", "dBmV"))
+	snr, ok := e.thresholds.DownstreamSNR[ChannelTypeOFDM.String()]
+	if !ok {
+		snr = e.thresholds.DefaultDownstreamSNR
+	}
+	result.update(snr.rate(c.MERDataDB, "MER", "dB"))
+	return result
+}
+
+// EvaluateUpstreamChannel evaluates the health of the upstream channel.
+func (e *HealthEvaluator) EvaluateUpstreamChannel(c *UpstreamChannelInfo) ChannelHealth {
+	result := ChannelHealth{
+		Direction:   ChannelUpstream,
+		ChannelID:   c.ChannelID,
+		FrequencyHZ: c.FrequencyHZ,
+		Modulation:  c.Modulation,
+	}
+	if !c.Locked {
+		result.update(HealthBad, "not locked")
+		return result
+	}
+
+	result.update(e.thresholds.UpstreamPower.rate(c.SignalPowerDBMV, "power", "dBmV"))
+	return result
+}
+
+// EvaluateOFDMAUpstreamChannel evaluates the health of the DOCSIS 3.1 OFDMA
+// upstream channel.
+func (e *HealthEvaluator) EvaluateOFDMAUpstreamChannel(c *OFDMAUpstreamChannelInfo) ChannelHealth {
+	result := ChannelHealth{
+		Direction:   ChannelUpstream,
+		ChannelID:   c.ChannelID,
+		FrequencyHZ: c.SubcarrierZeroFrequencyHZ,
+		Modulation:  ChannelTypeOFDMA.String(),
+	}
+	if !c.Locked {
+		result.update(HealthBad, "not locked")
+		return result
+	}
+
+	result.update(e.thresholds.UpstreamPower.rate(c.SignalPowerDBMV, "power", "dBmV"))
+	return result
+}
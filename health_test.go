@@ -0,0 +1,142 @@
+package cablemodemutil
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+var evaluateDownstreamChannelTests = []struct {
+	name        string
+	channel     DownstreamChannelInfo
+	want        HealthRating
+	wantReasons []string
+}{
+	{
+		name:    "Good QAM256",
+		channel: DownstreamChannelInfo{Locked: true, Modulation: "QAM256", SignalPowerDBMV: 4.3, SignalSNRMERDB: 40.9},
+		want:    HealthGood,
+	},
+	{
+		name:        "Not locked",
+		channel:     DownstreamChannelInfo{Locked: false, Modulation: "QAM256", SignalPowerDBMV: 4.3, SignalSNRMERDB: 40.9},
+		want:        HealthBad,
+		wantReasons: []string{"not locked"},
+	},
+	{
+		name:        "Marginal power",
+		channel:     DownstreamChannelInfo{Locked: true, Modulation: "QAM256", SignalPowerDBMV: -9.5, SignalSNRMERDB: 40.9},
+		want:        HealthMarginal,
+		wantReasons: []string{"power -9.5 dBmV close to minimum -15.0 dBmV"},
+	},
+	{
+		name:    "Bad power and marginal SNR",
+		channel: DownstreamChannelInfo{Locked: true, Modulation: "QAM256", SignalPowerDBMV: 16.2, SignalSNRMERDB: 34.5},
+		want:    HealthBad,
+		wantReasons: []string{
+			"power 16.2 dBmV above maximum 15.0 dBmV",
+			"SNR/MER 34.5 dB close to minimum 33.0 dB",
+		},
+	},
+	{
+		name:    "Good QAM64 SNR below the QAM256 minimum",
+		channel: DownstreamChannelInfo{Locked: true, Modulation: "QAM64", SignalPowerDBMV: 0, SignalSNRMERDB: 31},
+		want:    HealthGood,
+	},
+	{
+		name: "Bad OFDM SNR by channel type",
+		channel: DownstreamChannelInfo{
+			Locked:          true,
+			Type:            ChannelTypeOFDM,
+			Modulation:      "OFDM PLC",
+			SignalPowerDBMV: 2.9,
+			SignalSNRMERDB:  34,
+		},
+		want:        HealthBad,
+		wantReasons: []string{"SNR/MER 34.0 dB below minimum 35.0 dB"},
+	},
+}
+
+func TestEvaluateDownstreamChannel(t *testing.T) {
+	e := NewHealthEvaluator(nil)
+	for _, tc := range evaluateDownstreamChannelTests {
+		got := e.EvaluateDownstreamChannel(&tc.channel)
+		if got.Rating != tc.want || !reflect.DeepEqual(got.Reasons, tc.wantReasons) {
+			t.Errorf("%q: EvaluateDownstreamChannel() = (%s, %q) want: (%s, %q)",
+				tc.name, got.Rating, got.Reasons, tc.want, tc.wantReasons)
+		}
+	}
+}
+
+func TestEvaluateUpstreamChannel(t *testing.T) {
+	thresholds := DefaultHealthThresholds()
+	thresholds.UpstreamPower.Max = 45
+	e := NewHealthEvaluator(&thresholds)
+
+	got := e.EvaluateUpstreamChannel(&UpstreamChannelInfo{Locked: true, ChannelID: 2, SignalPowerDBMV: 46})
+	want := []string{"power 46.0 dBmV above maximum 45.0 dBmV"}
+	if got.Rating != HealthBad || !reflect.DeepEqual(got.Reasons, want) {
+		t.Errorf("EvaluateUpstreamChannel() = (%s, %q) want: (%s, %q)", got.Rating, got.Reasons, HealthBad, want)
+	}
+}
+
+func TestHealthEvaluatorEvaluate(t *testing.T) {
+	st, err := ParseRawStatus(loadRawStatus(t, s33StatusFixture))
+	if err != nil {
+		t.Fatalf("ParseRawStatus() failed, reason: %s", err)
+	}
+
+	e := NewHealthEvaluator(nil)
+	got := e.Evaluate(st)
+	if got.Rating != HealthGood || len(got.Reasons) != 0 {
+		t.Errorf("Evaluate() = (%s, %q) want: (%s, [])", got.Rating, got.Reasons, HealthGood)
+	}
+	if len(got.Channels) != 7 {
+		t.Errorf("Evaluate() returned %d channels want: 7", len(got.Channels))
+	}
+
+	st.Connection.Downstream.Channels[1].Locked = false
+	st.Connection.Upstream.Channels[0].SignalPowerDBMV = 49.5
+	got = e.Evaluate(st)
+	want := []string{
+		"downstream channel 1 (QAM256, 585000000 Hz): not locked",
+		"upstream channel 1 (SC-QAM, 16400000 Hz): power 49.5 dBmV close to maximum 51.0 dBmV",
+	}
+	if got.Rating != HealthBad || !reflect.DeepEqual(got.Reasons, want) {
+		t.Errorf("Evaluate() = (%s, %q) want: (%s, %q)", got.Rating, got.Reasons, HealthBad, want)
+	}
+}
+
+func TestHealthEvaluatorEvaluateOFDM(t *testing.T) {
+	sections := SectionOFDMDownstreamChannels | SectionOFDMAUpstreamChannels
+	st, err := ParseRawStatusWithOptions(
+		loadRawStatus(t, "testdata/s33_ofdm_status.json"),
+		&StatusOptions{Sections: sections},
+	)
+	if err != nil {
+		t.Fatalf("ParseRawStatusWithOptions() failed, reason: %s", err)
+	}
+	// The OFDM channel also present in the OFDM channel list must be
+	// evaluated only once.
+	st.Connection.Downstream.Channels = []DownstreamChannelInfo{
+		{Locked: true, Type: ChannelTypeOFDM, ChannelID: 33, SignalSNRMERDB: 10},
+	}
+
+	e := NewHealthEvaluator(nil)
+	got := e.Evaluate(st)
+	if got.Rating != HealthGood || len(got.Reasons) != 0 {
+		t.Errorf("Evaluate() = (%s, %q) want: (%s, [])", got.Rating, got.Reasons, HealthGood)
+	}
+	if len(got.Channels) != 3 {
+		t.Errorf("Evaluate() returned %d channels want: 3", len(got.Channels))
+	}
+
+	st.Connection.Downstream.OFDMChannels[1].MERDataDB = 30
+	st.Connection.Upstream.OFDMAChannels[0].Locked = false
+	got = e.Evaluate(st)
+	if got.Rating != HealthBad || len(got.Reasons) != 2 ||
+		!strings.Contains(got.Reasons[0], "MER 30.0 dB below minimum 35.0 dB") ||
+		!strings.HasSuffix(got.Reasons[1], "not locked") {
+		t.Errorf("Evaluate() = (%s, %q) want the OFDM MER and the OFDMA lock failures", got.Rating, got.Reasons)
+	}
+}
@@ -25,20 +25,20 @@ func actionResultKey(action string) string {
 }
 
 // Generates the private key using the public key, challenge and the clear password.
-func genPrivateKey(publicKey string, challenge string, clearPassword string) (string, error) {
-	return genHMACMD5(publicKey+clearPassword, challenge)
+func genPrivateKey(alg HashAlgorithm, publicKey string, challenge string, clearPassword string) (string, error) {
+	return genHMAC(alg, publicKey+clearPassword, challenge)
 }
 
 // Generates the hashed password using the private key and challenge.
-func genHashedPassword(privateKey string, challenge string) (string, error) {
-	return genHMACMD5(privateKey, challenge)
+func genHashedPassword(alg HashAlgorithm, privateKey string, challenge string) (string, error) {
+	return genHMAC(alg, privateKey, challenge)
 }
 
 // Generates the HNAP auth for the request.
-func genHNAPAuth(privateKey string, soapAction string) (string, error) {
+func genHNAPAuth(alg HashAlgorithm, privateKey string, soapAction string) (string, error) {
 	currTime := time.Now().UnixMilli()
 	authMsg := fmt.Sprintf("%d%s", currTime, actionURI(soapAction))
-	auth, err := genHMACMD5(privateKey, authMsg)
+	auth, err := genHMAC(alg, privateKey, authMsg)
 	if err != nil {
 		return "", fmt.Errorf("HNAP auth generation failed, reason: %w", err)
 	}
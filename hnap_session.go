@@ -0,0 +1,564 @@
+package cablemodemutil
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"sync"
+	"time"
+)
+
+const (
+	urlFormat           = "%s://%s/HNAP1/"
+	tokenExpiryDuration = 10 * time.Minute
+	loginAction         = "Login"
+	queryAction         = "GetMultipleHNAPs"
+)
+
+// Minimum extension of the token expiry for saving the token in the token
+// store again, avoiding a write to the store on every request.
+const tokenStoreRefreshInterval = tokenExpiryDuration / 2
+
+// hnapSession maintains the authenticated HNAP1 session with the cable modem,
+// shared by all the drivers for the cable modems using HNAP1.
+type hnapSession struct {
+	client        *httpClient
+	host          string
+	username      string
+	clearPassword string
+	debug         RetrieverDebug
+	tokenStore    TokenStore
+	hashAlgorithm HashAlgorithm
+	tok           *token
+	// The token last saved in or loaded from the token store.
+	storedTok *token
+	tokMu     sync.Mutex
+}
+
+// The token object containing the state of the authenticated session with
+// the cable modem.
+type token struct {
+	// The UID of the session provided by the cable modem during
+	// authentication.
+	uid string
+	// The private key of the session after authentication, generated based
+	// on public key, challenge from the cable modem and the supplied password.
+	privateKey string
+	// The expiry timestamp of the credentials stored in this session.
+	expiry time.Time
+	// The hash algorithm used for the HNAP auth within this session.
+	alg HashAlgorithm
+}
+
+// Initial response from the cable modem to allow the client to initiate
+// authentication.
+type loginResponse struct {
+	// The UID of the session provided by the cable modem during
+	// authentication.
+	uid string
+	// The public key provided by the cable modem during authentication.
+	publicKey string
+	// The challenge message provided by the cable modem during authentication.
+	challenge string
+}
+
+// actionRequest represents the payload of the request containing the SOAP
+// action command.
+type actionRequest map[string]string
+
+// actionResponse represents the payload of the response to a SOAP action
+// command request.
+type actionResponse map[string]interface{}
+
+// soapRequest represents the full SOAP request body.
+type soapRequest map[string]actionRequest
+
+// soapResponse represents the full SOAP response body to a SOAP request sent.
+type soapResponse map[string]actionResponse
+
+// Returns a token that has been reset to the initial state.
+func resetToken() *token {
+	return &token{
+		privateKey: "withoutLoginKey",
+	}
+}
+
+// Encodes the specified payload for the specified action into a byte buffer
+// to send this as a request.
+func encodePayload(action string, payload actionRequest) (*bytes.Buffer, error) {
+	req := soapRequest{
+		action: payload,
+	}
+	buf := new(bytes.Buffer)
+	err := json.NewEncoder(buf).Encode(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode request as JSON, reason: %w", err)
+	}
+	return buf, nil
+}
+
+// Decodes the specified byte array response for the specified action into the
+// response payload.
+func decodePayload(action string, resp *[]byte) (actionResponse, error) {
+	var payload soapResponse
+	err := json.Unmarshal(*resp, &payload)
+	if err != nil {
+		return nil, &ResponseError{
+			Action: action,
+			Reason: "failed to decode response payload",
+			Err:    err,
+		}
+	}
+	return unpackResponse(action, payload)
+}
+
+// Validates and unpacks the response for the specified SOAP action.
+func unpackResponse(action string, resp soapResponse) (actionResponse, error) {
+	if len(resp) != 1 {
+		return nil, &ResponseError{
+			Action:   action,
+			Reason:   fmt.Sprintf("invalid number of keys (%d) in response, expected 1", len(resp)),
+			Response: prettyPrintJSON(resp),
+		}
+	}
+
+	respKey := actionResponseKey(action)
+	unpacked, keyExists := resp[respKey]
+	if !keyExists {
+		return nil, &ResponseError{
+			Action:   action,
+			Reason:   fmt.Sprintf("unable to find the response key %q in response", respKey),
+			Response: prettyPrintJSON(resp),
+		}
+	}
+
+	resultKey := actionResultKey(action)
+	result, keyExists := unpacked[resultKey]
+	if !keyExists {
+		return nil, &ResponseError{
+			Action:   action,
+			Reason:   fmt.Sprintf("unable to find the result key %q in unpacked response", resultKey),
+			Response: prettyPrintJSON(unpacked),
+		}
+	}
+	if result != "OK" {
+		return nil, &ResultError{
+			Action:   action,
+			Result:   fmt.Sprintf("%v", result),
+			Response: prettyPrintJSON(unpacked),
+		}
+	}
+
+	return unpacked, nil
+}
+
+// Returns a new HNAP1 session with the cable modem using the specified
+// configuration. The session is established lazily on the first request.
+func newHNAPSession(cfg *DriverConfig) *hnapSession {
+	url := fmt.Sprintf(urlFormat, cfg.Protocol, cfg.Host)
+	s := hnapSession{}
+	s.client = &httpClient{client: cfg.HTTPClient, url: url, debug: cfg.Debug}
+	s.host = cfg.Host
+	s.username = cfg.Username
+	s.clearPassword = cfg.ClearPassword
+	s.debug = cfg.Debug
+	s.tokenStore = cfg.TokenStore
+	s.hashAlgorithm = cfg.HashAlgorithm
+	s.tok = resetToken()
+	return &s
+}
+
+// Persist the token in the object.
+func (s *hnapSession) persistToken(tok *token) {
+	s.tokMu.Lock()
+	s.tok = &token{
+		privateKey: tok.privateKey,
+		uid:        tok.uid,
+		expiry:     tok.expiry,
+		alg:        tok.alg,
+	}
+	// Save the token in the store only if the session has changed, or the
+	// expiry in the store is well behind.
+	save := s.tokenStore != nil && (s.storedTok == nil ||
+		s.storedTok.uid != tok.uid ||
+		s.storedTok.privateKey != tok.privateKey ||
+		tok.expiry.Sub(s.storedTok.expiry) >= tokenStoreRefreshInterval)
+	if save {
+		s.storedTok = s.tok
+	}
+	s.tokMu.Unlock()
+	if s.debug.Debug {
+		fmt.Println("Persisting a new token.")
+		debugToken(tok)
+	}
+	if !save {
+		return
+	}
+
+	var stored *Token
+	if tok.uid != "" {
+		stored = &Token{
+			UID:           tok.uid,
+			PrivateKey:    tok.privateKey,
+			Expiry:        tok.expiry,
+			HashAlgorithm: tok.alg,
+		}
+	}
+	// Failing to persist the token in the store only costs a login in the
+	// future.
+	err := s.tokenStore.Save(s.host, s.username, stored)
+	if err != nil && s.debug.Debug {
+		fmt.Printf("Failed to save the token in the token store, reason: %s\n", err)
+	}
+}
+
+// Returns the token from the token store if available and unexpired, nil
+// otherwise.
+func (s *hnapSession) loadStoredToken() *token {
+	if s.tokenStore == nil {
+		return nil
+	}
+	stored, err := s.tokenStore.Load(s.host, s.username)
+	if err != nil {
+		if s.debug.Debug {
+			fmt.Printf("Failed to load the token from the token store, reason: %s\n", err)
+		}
+		return nil
+	}
+	if stored == nil || stored.UID == "" || time.Now().After(stored.Expiry) {
+		return nil
+	}
+	tok := &token{
+		uid:        stored.UID,
+		privateKey: stored.PrivateKey,
+		expiry:     stored.Expiry,
+		alg:        stored.HashAlgorithm,
+	}
+	s.tokMu.Lock()
+	s.storedTok = &token{uid: tok.uid, privateKey: tok.privateKey, expiry: tok.expiry, alg: tok.alg}
+	s.tokMu.Unlock()
+	return tok
+}
+
+// Retrieves a copy of the persisted token.
+func (s *hnapSession) getToken() *token {
+	s.tokMu.Lock()
+	res := &token{
+		privateKey: s.tok.privateKey,
+		uid:        s.tok.uid,
+		expiry:     s.tok.expiry,
+		alg:        s.tok.alg,
+	}
+	s.tokMu.Unlock()
+	return res
+}
+
+// Sends the SOAP request for the specified action containing the specified
+// payload.
+func (s *hnapSession) sendReq(
+	ctx context.Context,
+	action string,
+	payload actionRequest,
+	tok *token,
+) (actionResponse, error) {
+	req, err := encodePayload(action, payload)
+	if err != nil {
+		return nil, err
+	}
+	resp, err := s.client.sendPOST(ctx, action, req, tok)
+	if err != nil {
+		return nil, err
+	}
+	return decodePayload(action, resp)
+}
+
+// Retrieves the cookie, public key and challenge information from the cable
+// modem that can be used for initiating an authentication request using the
+// specified hash algorithm.
+func (s *hnapSession) getLoginResponse(ctx context.Context, alg HashAlgorithm) (*loginResponse, error) {
+	payload := actionRequest{
+		"LoginPassword": "",
+		"Captcha":       "",
+		"PrivateLogin":  "LoginPassword",
+		"Action":        "request",
+		"Username":      s.username,
+	}
+	tok := resetToken()
+	tok.alg = alg
+	resp, err := s.sendReq(ctx, loginAction, payload, tok)
+	if errors.Is(err, ErrResultNotOK) {
+		// The cable modem reports a failed result for unknown users.
+		return nil, &AuthError{Username: s.username, Err: err}
+	}
+	if hashAlgorithmRejected(err) {
+		return nil, &AuthError{Username: s.username, Err: err}
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to retrieve login challenge\nreason: %w", err)
+	}
+
+	respBody := actionResponseBody(resp)
+	result := loginResponse{}
+	result.uid, err = parseString(respBody, "Cookie", "Cookie in login response")
+	if err != nil {
+		return nil, err
+	}
+	result.publicKey, err = parseString(respBody, "PublicKey", "Public key in login response")
+	if err != nil {
+		return nil, err
+	}
+	result.challenge, err = parseString(respBody, "Challenge", "Challenge in login response")
+	if err != nil {
+		return nil, err
+	}
+
+	return &result, nil
+}
+
+// Returns true if the specified error indicates the cable modem rejected the
+// HNAP auth of the login challenge request, which happens when the auth is
+// generated using a hash algorithm other than its own.
+func hashAlgorithmRejected(err error) bool {
+	var statusErr *HTTPStatusError
+	return errors.As(err, &statusErr) && statusErr.StatusCode == http.StatusUnauthorized
+}
+
+// Performs authentication with the cable modem and returns the response.
+func (s *hnapSession) doAuth(ctx context.Context, challenge string, tok *token) error {
+	hashedPassword, err := genHashedPassword(tok.alg, tok.privateKey, challenge)
+	if err != nil {
+		return fmt.Errorf("auth failed while generating hashed password, reason: %w", err)
+	}
+
+	payload := actionRequest{
+		"LoginPassword": hashedPassword,
+		"Captcha":       "",
+		"PrivateLogin":  "LoginPassword",
+		"Action":        "login",
+		"Username":      s.username,
+	}
+	_, err = s.sendReq(ctx, loginAction, payload, tok)
+	if errors.Is(err, ErrResultNotOK) {
+		// The cable modem reports a failed result when the credentials are
+		// rejected.
+		return &AuthError{Username: s.username, Err: err}
+	}
+	if err != nil {
+		return fmt.Errorf("auth failed.\nreason: %w", err)
+	}
+	return nil
+}
+
+// Login to the cable modem using the specified username and password,
+// negotiating the hash algorithm if required. The next algorithm is tried
+// only if the cable modem rejects the login challenge request using the
+// current algorithm, and not if it rejects the password, to avoid an
+// additional failed login for the wrong password.
+func (s *hnapSession) login(ctx context.Context) (*token, error) {
+	algs := s.hashAlgorithm.candidates()
+	if prev := s.getToken().alg; s.hashAlgorithm == HashAlgorithmAuto && prev == HashAlgorithmSHA256 {
+		// Try the algorithm negotiated previously first.
+		algs = []HashAlgorithm{HashAlgorithmSHA256, HashAlgorithmMD5}
+	}
+
+	var err, firstErr error
+	for i, alg := range algs {
+		var tok *token
+		tok, err = s.loginWith(ctx, alg)
+		if err == nil {
+			return tok, nil
+		}
+		if i > 0 {
+			// Retain the failure from the first attempt, in case the actual
+			// reason for the failures is not the hash algorithm.
+			return nil, fmt.Errorf("%s: %v; %s: %w", algs[0], firstErr, alg, err)
+		}
+		if !hashAlgorithmRejected(err) {
+			return nil, err
+		}
+		if s.debug.Debug {
+			fmt.Printf("Login using the %s hash algorithm failed, reason: %s\n", alg, err)
+		}
+		firstErr = err
+	}
+	return nil, err
+}
+
+// Login to the cable modem using the specified hash algorithm.
+func (s *hnapSession) loginWith(ctx context.Context, alg HashAlgorithm) (*token, error) {
+	loginResp, err := s.getLoginResponse(ctx, alg)
+	if err != nil {
+		return nil, err
+	}
+	// Compute the expiry time as soon as we obtain the response.
+	expiry := time.Now().Add(tokenExpiryDuration)
+
+	privateKey, err := genPrivateKey(alg, loginResp.publicKey, loginResp.challenge, s.clearPassword)
+	if err != nil {
+		return nil, fmt.Errorf("login failed while generating private key, reason: %w", err)
+	}
+
+	tok := &token{
+		uid:        loginResp.uid,
+		privateKey: privateKey,
+		expiry:     expiry,
+		alg:        alg,
+	}
+	err = s.doAuth(ctx, loginResp.challenge, tok)
+	if err != nil {
+		return nil, err
+	}
+	return tok, nil
+}
+
+// Logs in to the cable modem discarding the current session if any, and
+// persists the new session.
+func (s *hnapSession) authenticate(ctx context.Context) error {
+	tok, err := s.login(ctx)
+	if err != nil {
+		return err
+	}
+	s.persistToken(tok)
+	return nil
+}
+
+// Discards the current session, causing the next request to login again.
+func (s *hnapSession) reset() {
+	tok := resetToken()
+	// Retain the negotiated hash algorithm for the next login.
+	tok.alg = s.getToken().alg
+	s.persistToken(tok)
+}
+
+// Sends the SOAP request for the specified action containing the specified
+// payload within an authenticated session, logging in to the cable modem
+// if the persisted token has expired. If retry is true and the cable modem
+// reports the session as expired, logs in again and re-sends the request.
+// Non-idempotent actions must not be retried, since the cable modem might
+// have carried out the request already.
+func (s *hnapSession) sendAuthenticatedReq(
+	ctx context.Context,
+	action string,
+	payload actionRequest,
+	retry bool,
+) (actionResponse, error) {
+	var err error
+	loginAttempted := false
+
+	tok := s.getToken()
+	// Reuse the session from the token store (possibly persisted by another
+	// process) before attempting to login.
+	if time.Now().After(tok.expiry) {
+		if stored := s.loadStoredToken(); stored != nil {
+			if s.debug.Debug {
+				fmt.Println("Using the token from the token store.")
+				debugToken(stored)
+			}
+			tok = stored
+		}
+	}
+	for {
+		// If the token has expired, login to generate a fresh token.
+		if time.Now().After(tok.expiry) {
+			if s.debug.Debug {
+				fmt.Println("Token expired, will attempt a new login.")
+				debugToken(tok)
+			}
+			loginAttempted = true
+			tok, err = s.login(ctx)
+			if err != nil {
+				return nil, err
+			}
+			s.persistToken(tok)
+		}
+
+		var resp actionResponse
+		// Compute the new token expiry time based on when we send the request.
+		newExpiry := time.Now().Add(tokenExpiryDuration)
+		resp, err = s.sendReq(ctx, action, payload, tok)
+		if err == nil {
+			tok.expiry = newExpiry
+			s.persistToken(tok)
+			return resp, nil
+		}
+
+		// There is no point in re-attempting if the context has been
+		// cancelled or its deadline has been exceeded.
+		if ctx.Err() != nil {
+			break
+		}
+
+		// If the session has expired, and we didn't generate a fresh token
+		// just now, generate a new token and re-attempt sending the request.
+		// Any other failure (Eg. a lost response) is returned as is since
+		// the cable modem might have carried out the request already.
+		if !retry || loginAttempted || !errors.Is(err, ErrSessionExpired) {
+			break
+		}
+		tok = resetToken()
+	}
+	return nil, err
+}
+
+// Sends the specified SOAP action containing the specified payload within an
+// authenticated session, and returns the unpacked response. The request is
+// re-sent after logging in again if the session has expired, only if retry
+// is true.
+func (s *hnapSession) do(ctx context.Context, action string, payload map[string]string, retry bool) (ActionResponse, error) {
+	req := make(actionRequest, len(payload))
+	for k, v := range payload {
+		req[k] = v
+	}
+	resp, err := s.sendAuthenticatedReq(ctx, action, req, retry)
+	if err != nil {
+		return nil, err
+	}
+	return ActionResponse(resp), nil
+}
+
+// Queries the specified actions in a single GetMultipleHNAPs request within
+// an authenticated session, and returns the unpacked responses keyed by the
+// action.
+func (s *hnapSession) getMultiple(ctx context.Context, actions ...string) (map[string]ActionResponse, error) {
+	payload := make(actionRequest, len(actions))
+	for _, action := range actions {
+		payload[action] = ""
+	}
+	resp, err := s.sendAuthenticatedReq(ctx, queryAction, payload, true)
+	if err != nil {
+		return nil, err
+	}
+
+	status := CableModemRawStatus(resp)
+	result := make(map[string]ActionResponse, len(actions))
+	for _, action := range actions {
+		err = validateSubResponse(status, action)
+		if err != nil {
+			return nil, err
+		}
+		var unpacked actionResponseBody
+		unpacked, err = actionResp(status, action)
+		if err != nil {
+			return nil, err
+		}
+		result[action] = ActionResponse(unpacked)
+	}
+	return result, nil
+}
+
+// Queries the specified sub-commands in a single GetMultipleHNAPs request
+// within an authenticated session, and returns the raw status.
+func (s *hnapSession) queryRawStatus(ctx context.Context, cmds []string) (CableModemRawStatus, error) {
+	payload := make(actionRequest, len(cmds))
+	for _, cmd := range cmds {
+		payload[cmd] = ""
+	}
+	status, err := s.sendAuthenticatedReq(ctx, queryAction, payload, true)
+	if err != nil {
+		return nil, err
+	}
+	return CableModemRawStatus(status), nil
+}
@@ -0,0 +1,153 @@
+package cablemodemutil
+
+import (
+	"html"
+	"strings"
+)
+
+// A table in an HTML page, containing the text of the cells in each row.
+type htmlTable [][]string
+
+// Parses the tables in the specified HTML page. Nested tables are returned
+// as separate tables, with the text of the nested table omitted from the
+// enclosing cell. The contents of scripts, styles and comments are ignored.
+//
+// This is deliberately minimal and lenient, only handling the markup seen
+// in the status pages of the cable modems rather than HTML in general.
+// nolint:cyclop
+func parseHTMLTables(page string) []htmlTable {
+	type tableState struct {
+		rows htmlTable
+		cell *strings.Builder
+	}
+	var (
+		result []htmlTable
+		stack  []*tableState
+	)
+	// Ends the current cell, if any, of the innermost table.
+	endCell := func() {
+		if len(stack) == 0 {
+			return
+		}
+		t := stack[len(stack)-1]
+		if t.cell == nil {
+			return
+		}
+		if len(t.rows) == 0 {
+			// Cells outside of a row.
+			t.rows = append(t.rows, nil)
+		}
+		last := len(t.rows) - 1
+		t.rows[last] = append(t.rows[last], normalizeHTMLText(t.cell.String()))
+		t.cell = nil
+	}
+
+	for len(page) > 0 {
+		lt := strings.IndexByte(page, '<')
+		if lt < 0 {
+			lt = len(page)
+		}
+		if len(stack) > 0 && stack[len(stack)-1].cell != nil {
+			stack[len(stack)-1].cell.WriteString(page[:lt])
+		}
+		page = page[lt:]
+		if page == "" {
+			break
+		}
+
+		if strings.HasPrefix(page, "<!--") {
+			end := strings.Index(page, "-->")
+			if end < 0 {
+				break
+			}
+			page = page[end+len("-->"):]
+			continue
+		}
+		gt := strings.IndexByte(page, '>')
+		if gt < 0 {
+			break
+		}
+		name, closing := htmlTagName(page[1:gt])
+		page = page[gt+1:]
+
+		switch {
+		case (name == "script" || name == "style") && !closing:
+			end := strings.Index(strings.ToLower(page), "</"+name)
+			if end < 0 {
+				page = ""
+				continue
+			}
+			page = page[end:]
+		case name == "table" && !closing:
+			stack = append(stack, &tableState{})
+		case name == "table" && closing:
+			if len(stack) == 0 {
+				continue
+			}
+			endCell()
+			result = append(result, stack[len(stack)-1].rows)
+			stack = stack[:len(stack)-1]
+		case len(stack) == 0:
+			continue
+		case name == "tr":
+			endCell()
+			if !closing {
+				t := stack[len(stack)-1]
+				t.rows = append(t.rows, nil)
+			}
+		case name == "td" || name == "th":
+			endCell()
+			if !closing {
+				stack[len(stack)-1].cell = &strings.Builder{}
+			}
+		case name == "br" || name == "p" || name == "div":
+			// Block level elements separate the words within the cell.
+			if stack[len(stack)-1].cell != nil {
+				stack[len(stack)-1].cell.WriteString(" ")
+			}
+		}
+	}
+	return result
+}
+
+// Returns the lower case name of the specified tag contents (i.e. the text
+// within the angle brackets), and whether it is a closing tag.
+func htmlTagName(tag string) (string, bool) {
+	closing := strings.HasPrefix(tag, "/")
+	tag = strings.TrimPrefix(tag, "/")
+	end := strings.IndexAny(tag, " \t\r\n/")
+	if end >= 0 {
+		tag = tag[:end]
+	}
+	return strings.ToLower(tag), closing
+}
+
+// Returns the specified HTML text with the character references decoded and
+// the whitespace collapsed.
+func normalizeHTMLText(text string) string {
+	return strings.Join(strings.Fields(html.UnescapeString(text)), " ")
+}
+
+// Returns the index of the header row in the table, identified as the first
+// row containing all the specified column names (case insensitive), along
+// with the index of each of the columns in the row keyed by the lower case
+// column name. Returns -1 if no such row exists.
+func (t htmlTable) header(columns ...string) (int, map[string]int) {
+	for i, row := range t {
+		cols := make(map[string]int, len(row))
+		for j, cell := range row {
+			cols[strings.ToLower(cell)] = j
+		}
+		found := true
+		for _, c := range columns {
+			if _, ok := cols[c]; !ok {
+				found = false
+				break
+			}
+		}
+		if found {
+			return i, cols
+		}
+	}
+	return -1, nil
+}
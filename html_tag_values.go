@@ -0,0 +1,95 @@
+package cablemodemutil
+
+import (
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// Matches the tag-value list assigned within a JavaScript function.
+// nolint:gochecknoglobals
+var tagValueListRegexp = regexp.MustCompile(`tagValueList\s*=\s*(?:'([^']*)'|"([^"]*)")`)
+
+// Returns the values in the tag-value list (Eg. '2|1|Locked|QAM256|...')
+// assigned within the specified JavaScript function in the page, or nil if
+// unavailable.
+func jsTagValues(page string, fn string) []string {
+	start := regexp.MustCompile(`function\s+` + regexp.QuoteMeta(fn) + `\s*\(`).FindStringIndex(page)
+	if start == nil {
+		return nil
+	}
+	body := page[start[1]:]
+	// Limit the search to the body of the function.
+	if end := strings.Index(body, "function "); end >= 0 {
+		body = body[:end]
+	}
+	m := tagValueListRegexp.FindStringSubmatch(body)
+	if m == nil {
+		return nil
+	}
+	list := m[1] + m[2]
+	values := strings.Split(list, "|")
+	for i, v := range values {
+		values[i] = normalizeHTMLText(v)
+	}
+	// The lists are terminated by a trailing '|'.
+	if len(values) > 0 && values[len(values)-1] == "" {
+		values = values[:len(values)-1]
+	}
+	return values
+}
+
+// Returns the channel table for the specified channel tag-value list, whose
+// first value is the number of channels followed by the values for each of
+// the channels, or nil if the list is malformed.
+func tagValuesChannelTable(values []string, header []string) htmlTable {
+	if len(values) == 0 {
+		return nil
+	}
+	count, err := strconv.Atoi(values[0])
+	if err != nil || count < 0 {
+		return nil
+	}
+	values = values[1:]
+	if n := len(values) / len(header); n < count {
+		count = n
+	}
+	t := htmlTable{header}
+	for i := 0; i < count; i++ {
+		t = append(t, values[i*len(header):(i+1)*len(header)])
+	}
+	return t
+}
+
+// Parses the tag-value lists returned by the Init*TagValue() JavaScript
+// functions in the Netgear CM series status page into tables with the same
+// layout as the tables populated from the lists by the page, since the
+// tables in the page itself are empty until the scripts run.
+func parseNetgearTagValueTables(page string) []htmlTable {
+	var result []htmlTable
+	if v := jsTagValues(page, "InitTagValue"); len(v) >= 10 {
+		result = append(result, htmlTable{
+			{"Procedure", "Status", "Comment"},
+			{"Acquire Downstream Channel", v[0], v[1]},
+			{"Connectivity State", v[2], v[3]},
+			{"Boot State", v[4], v[5]},
+			{"Configuration File", v[6], v[7]},
+			{"Security", v[8], v[9]},
+		})
+	}
+	ds := tagValuesChannelTable(jsTagValues(page, "InitDsTableTagValue"), []string{
+		"Channel", "Lock Status", "Modulation", "Channel ID", "Frequency",
+		"Power", "SNR", "Correctables", "Uncorrectables",
+	})
+	if ds != nil {
+		result = append(result, ds)
+	}
+	us := tagValuesChannelTable(jsTagValues(page, "InitUsTableTagValue"), []string{
+		"Channel", "Lock Status", "US Channel Type", "Channel ID", "Symbol Rate",
+		"Frequency", "Power",
+	})
+	if us != nil {
+		result = append(result, us)
+	}
+	return result
+}
@@ -1,17 +1,15 @@
 package cablemodemutil
 
 import (
-	"crypto/tls"
+	"context"
 	"fmt"
 	"io"
 	"net/http"
-	"time"
 )
 
 const (
 	actionHeader           = "SOAPAction"
 	hnapAuthHeader         = "HNAP_AUTH"
-	connectionTimeout      = 15
 	contentTypeHeader      = "Content-Type"
 	contentTypeHeaderValue = "application/json; charset=UTF-8"
 )
@@ -42,7 +40,7 @@ func genCookies(tok *token) []*http.Cookie {
 
 // Adds the necessary headers and cookies to the HTTP request for the specified SOAP action.
 func addHeadersAndCookies(req *http.Request, action string, tok *token) error {
-	auth, err := genHNAPAuth(tok.privateKey, action)
+	auth, err := genHNAPAuth(tok.alg, tok.privateKey, action)
 	if err != nil {
 		return err
 	}
@@ -57,29 +55,17 @@ func addHeadersAndCookies(req *http.Request, action string, tok *token) error {
 	return nil
 }
 
+// Sends the HNAP1 requests to the cable modem.
 type httpClient struct {
 	client *http.Client
 	url    string
 	debug  RetrieverDebug
 }
 
-func newHTTPClient(url string, skipVerifyCert bool, debug *RetrieverDebug) *httpClient {
-	c := httpClient{}
-	c.client = &http.Client{
-		Timeout: connectionTimeout * time.Second,
-	}
-	c.client.Transport = &http.Transport{
-		TLSClientConfig: &tls.Config{InsecureSkipVerify: skipVerifyCert}, // nolint:gosec
-	}
-	c.url = url
-	c.debug = *debug
-	return &c
-}
-
 // Sends the HTTP POST request for the specified SOAP action containing the specified payload.
 // nolint:funlen
-func (c *httpClient) sendPOST(action string, payload io.Reader, tok *token) (*[]byte, error) {
-	req, err := http.NewRequest("POST", c.url, payload)
+func (c *httpClient) sendPOST(ctx context.Context, action string, payload io.Reader, tok *token) (*[]byte, error) {
+	req, err := http.NewRequestWithContext(ctx, "POST", c.url, payload)
 	if err != nil {
 		return nil, fmt.Errorf("unable to create POST request, reason: %w", err)
 	}
@@ -127,24 +113,11 @@ func (c *httpClient) sendPOST(action string, payload io.Reader, tok *token) (*[]
 	}
 
 	if resp.StatusCode != 200 {
-		// TODO: Convert this case into a specific error type that allows the
-		// caller to retry explicitly.
-		if resp.StatusCode == 404 && action == queryAction {
-			return nil, fmt.Errorf(
-				"HTTP POST request for SOAP action %q failed with 404"+
-					"status code possibly due to credentials having expired.\n"+
-					"body:%s",
-				action,
-				string(body),
-			)
+		return nil, &HTTPStatusError{
+			Action:     action,
+			StatusCode: resp.StatusCode,
+			Body:       string(body),
 		}
-		return nil, fmt.Errorf(
-			"HTTP POST request for SOAP action %q failed due to non-success"+
-				"status code: %d\nbody:%s",
-			action,
-			resp.StatusCode,
-			string(body),
-		)
 	}
 
 	return &body, nil
@@ -0,0 +1,98 @@
+package cablemodemutil
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"sort"
+	"strconv"
+	"time"
+)
+
+// LogCursor remembers the latest entry seen in the Cable Modem Log, so that
+// only the entries logged since can be retrieved. The cursor can be encoded
+// as JSON to persist it across restarts. It is not safe for concurrent use.
+type LogCursor struct {
+	// Timestamp of the latest seen entry.
+	Timestamp time.Time `json:"timestamp"`
+	// Content hashes of the seen entries with the latest timestamp, since
+	// multiple entries can be logged with the same timestamp.
+	Hashes []string `json:"hashes,omitempty"`
+}
+
+// Next returns the entries in the specified logs which are newer than the
+// cursor in chronological order, and advances the cursor past them.
+//
+// The log rolls over by dropping the oldest entries, so the latest seen
+// entry being absent from a non-empty log indicates that the log was
+// cleared (possibly with the entries logged since timestamped before the
+// cable modem synchronized its clock), in which case all the entries are
+// considered new.
+func (c *LogCursor) Next(logs []LogEntry) []LogEntry {
+	sorted := make([]LogEntry, len(logs))
+	copy(sorted, logs)
+	sort.SliceStable(sorted, func(i, j int) bool {
+		return sorted[i].Timestamp.Before(sorted[j].Timestamp)
+	})
+
+	// Number of the seen entries with the latest timestamp yet to be
+	// matched, keyed by the hash.
+	seen := make(map[string]int)
+	for _, h := range c.Hashes {
+		seen[h]++
+	}
+	var res []LogEntry
+	found := false
+	for _, l := range sorted {
+		if l.Timestamp.Equal(c.Timestamp) {
+			if h := logEntryHash(&l); seen[h] > 0 {
+				seen[h]--
+				found = true
+				continue
+			}
+		}
+		if !l.Timestamp.Before(c.Timestamp) {
+			res = append(res, l)
+		}
+	}
+	if !found && len(c.Hashes) > 0 && len(sorted) > 0 {
+		res = sorted
+	}
+
+	c.advance(res)
+	return res
+}
+
+// Advances the cursor past the specified new entries in chronological order.
+func (c *LogCursor) advance(logs []LogEntry) {
+	if len(logs) == 0 {
+		return
+	}
+	latest := logs[len(logs)-1].Timestamp
+	if !latest.Equal(c.Timestamp) {
+		c.Timestamp = latest
+		c.Hashes = nil
+	}
+	for i := len(logs) - 1; i >= 0 && logs[i].Timestamp.Equal(latest); i-- {
+		c.Hashes = append(c.Hashes, logEntryHash(&logs[i]))
+	}
+}
+
+// Returns the content hash of the log entry.
+func logEntryHash(l *LogEntry) string {
+	h := sha256.New()
+	h.Write([]byte(strconv.FormatInt(l.Timestamp.UnixNano(), 10)))
+	h.Write([]byte{0})
+	h.Write([]byte(l.Log))
+	return hex.EncodeToString(h.Sum(nil)[:16])
+}
+
+// NewLogEntries retrieves the Cable Modem Log, and returns the entries newer
+// than the specified cursor, advancing the cursor past them.
+func (r *Retriever) NewLogEntries(ctx context.Context, cursor *LogCursor) ([]LogEntry, error) {
+	st, err := r.StatusWithOptions(ctx, &StatusOptions{Sections: SectionLogs})
+	if err != nil {
+		return nil, err
+	}
+	return cursor.Next(st.Logs), nil
+}
@@ -0,0 +1,100 @@
+package cablemodemutil
+
+import (
+	"context"
+	"encoding/json"
+	"reflect"
+	"testing"
+	"time"
+)
+
+// Returns log entries with the specified messages, all timestamped at the
+// specified time.
+func logCursorTestEntries(ts time.Time, msgs ...string) []LogEntry {
+	res := make([]LogEntry, len(msgs))
+	for i, msg := range msgs {
+		res[i] = LogEntry{Timestamp: ts, Log: msg}
+	}
+	return res
+}
+
+// Returns the messages in the specified log entries.
+func logMessages(logs []LogEntry) []string {
+	var res []string
+	for _, l := range logs {
+		res = append(res, l.Log)
+	}
+	return res
+}
+
+func TestLogCursorNext(t *testing.T) {
+	t1 := time.Date(2021, 10, 22, 10, 1, 15, 0, time.UTC)
+	t2 := t1.Add(time.Minute)
+	t3 := t2.Add(time.Minute)
+
+	var c LogCursor
+	logs := append(logCursorTestEntries(t1, "a"), logCursorTestEntries(t2, "b", "c")...)
+	if got := logMessages(c.Next(logs)); !reflect.DeepEqual(got, []string{"a", "b", "c"}) {
+		t.Errorf("Next() with an empty cursor = %q want: all the entries", got)
+	}
+	if got := c.Next(logs); len(got) != 0 {
+		t.Errorf("Next() with unchanged logs = %q want: no entries", logMessages(got))
+	}
+
+	// New entries with the same timestamp as the latest seen entry, including
+	// a duplicate of a seen entry.
+	logs = append(logs, logCursorTestEntries(t2, "d", "b")...)
+	if got := logMessages(c.Next(logs)); !reflect.DeepEqual(got, []string{"d", "b"}) {
+		t.Errorf("Next() with entries at the same timestamp = %q want: %q", got, []string{"d", "b"})
+	}
+
+	// The log rolls over, dropping the oldest entries.
+	logs = append(logs[3:], logCursorTestEntries(t3, "e")...)
+	if got := logMessages(c.Next(logs)); !reflect.DeepEqual(got, []string{"e"}) {
+		t.Errorf("Next() after the log rolled over = %q want: %q", got, []string{"e"})
+	}
+
+	// The cursor survives a restart.
+	data, err := json.Marshal(&c)
+	if err != nil {
+		t.Fatalf("json.Marshal() failed, reason: %s", err)
+	}
+	var restored LogCursor
+	if err = json.Unmarshal(data, &restored); err != nil {
+		t.Fatalf("json.Unmarshal() failed, reason: %s", err)
+	}
+	if got := restored.Next(logs); len(got) != 0 {
+		t.Errorf("Next() with a restored cursor = %q want: no entries", logMessages(got))
+	}
+
+	// The log is cleared, and the new entries are logged before the clock
+	// is synchronized.
+	if got := restored.Next(nil); len(got) != 0 {
+		t.Errorf("Next() with a cleared log = %q want: no entries", logMessages(got))
+	}
+	logs = logCursorTestEntries(time.Date(1970, 1, 1, 0, 0, 10, 0, time.UTC), "f")
+	if got := logMessages(restored.Next(logs)); !reflect.DeepEqual(got, []string{"f"}) {
+		t.Errorf("Next() after the log was cleared = %q want: %q", got, []string{"f"})
+	}
+}
+
+func TestRetrieverNewLogEntries(t *testing.T) {
+	r, _ := newTestRetriever(t, testPassword)
+
+	var c LogCursor
+	got, err := r.NewLogEntries(context.Background(), &c)
+	if err != nil {
+		t.Fatalf("NewLogEntries() failed, reason: %s", err)
+	}
+	if len(got) != 5 {
+		t.Errorf("NewLogEntries() returned %d entries want: 5", len(got))
+	}
+
+	got, err = r.NewLogEntries(context.Background(), &c)
+	if err != nil {
+		t.Fatalf("NewLogEntries() failed, reason: %s", err)
+	}
+	if len(got) != 0 {
+		t.Errorf("NewLogEntries() returned %d entries want: 0", len(got))
+	}
+}
@@ -0,0 +1,122 @@
+package cablemodemutil
+
+import (
+	"net"
+	"sort"
+	"strings"
+	"time"
+)
+
+// LoginEvent contains a login attempt extracted from the Cable Modem Log.
+type LoginEvent struct {
+	// Timestamp of the login attempt.
+	Timestamp time.Time
+	// IP address from which the login was attempted, empty if unavailable
+	// in the log entry.
+	SourceIP string
+	// User name used for the login attempt, empty if unavailable in the
+	// log entry.
+	Username string
+	// True if the login attempt was successful, false otherwise.
+	Success bool
+}
+
+// LoginSourceCounts contains the number of login attempts from a source.
+type LoginSourceCounts struct {
+	// Number of successful login attempts.
+	Successes uint32
+	// Number of failed login attempts.
+	Failures uint32
+}
+
+// LoginAudit contains the login attempts extracted from the Cable Modem Log.
+type LoginAudit struct {
+	// Login attempts in chronological order.
+	Events []LoginEvent
+	// Number of login attempts keyed by the source IP address.
+	Sources map[string]LoginSourceCounts
+	// Latest successful login attempt, nil if none.
+	LatestSuccess *LoginEvent
+	// Latest failed login attempt, nil if none.
+	LatestFailure *LoginEvent
+}
+
+// Keys preceding the user name in the log message for a login attempt.
+// nolint:gochecknoglobals
+var loginUsernameKeys = map[string]bool{
+	"user":     true,
+	"username": true,
+}
+
+// ExtractLoginAudit extracts the login attempts from the specified log
+// entries.
+func ExtractLoginAudit(logs []LogEntry) *LoginAudit {
+	result := LoginAudit{
+		Sources: make(map[string]LoginSourceCounts),
+	}
+	for _, l := range logs {
+		if l.Event != LogEventLoginSuccess && l.Event != LogEventLoginFailure {
+			continue
+		}
+
+		msg := l.Message
+		if msg == "" {
+			msg = l.Log
+		}
+		ip, username := parseLoginMessage(msg)
+		result.Events = append(result.Events, LoginEvent{
+			Timestamp: l.Timestamp,
+			SourceIP:  ip,
+			Username:  username,
+			Success:   l.Event == LogEventLoginSuccess,
+		})
+	}
+
+	sort.SliceStable(result.Events, func(i, j int) bool {
+		return result.Events[i].Timestamp.Before(result.Events[j].Timestamp)
+	})
+	for i := range result.Events {
+		e := &result.Events[i]
+		counts := result.Sources[e.SourceIP]
+		if e.Success {
+			counts.Successes++
+			result.LatestSuccess = e
+		} else {
+			counts.Failures++
+			result.LatestFailure = e
+		}
+		result.Sources[e.SourceIP] = counts
+	}
+	return &result
+}
+
+// Parses the source IP address and the user name from the specified log
+// message for a login attempt. The user name is expected either as the word
+// following a "user" / "username" key (Eg. "username admin"), or combined
+// with the key using a ':' or '=' separator (Eg. "user=admin").
+// Eg. "Login Successful from 192.168.0.10 username admin"
+func parseLoginMessage(msg string) (string, string) {
+	var ip, username string
+	words := strings.Fields(msg)
+	for i, w := range words {
+		w = strings.Trim(w, ",;()[]'\"")
+		if ip == "" && net.ParseIP(w) != nil {
+			ip = w
+			continue
+		}
+		if username != "" {
+			continue
+		}
+
+		kv := strings.FieldsFunc(w, func(c rune) bool { return c == ':' || c == '=' })
+		if len(kv) == 0 || !loginUsernameKeys[strings.ToLower(kv[0])] {
+			continue
+		}
+		if len(kv) > 1 {
+			username = kv[1]
+		} else if i+1 < len(words) {
+			username = strings.Trim(words[i+1], ",;()[]'\"")
+		}
+	}
+	return ip, username
+}
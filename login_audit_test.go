@@ -0,0 +1,68 @@
+package cablemodemutil
+
+import (
+	"testing"
+)
+
+var parseLoginMessageTests = []struct {
+	name         string
+	msg          string
+	wantIP       string
+	wantUsername string
+}{
+	{
+		name:         "IPv4 and username",
+		msg:          "Login Successful from 192.168.0.10 username admin",
+		wantIP:       "192.168.0.10",
+		wantUsername: "admin",
+	},
+	{
+		name:         "IPv6 and user key-value",
+		msg:          "Login Failed (user=technician) from fe80::1",
+		wantIP:       "fe80::1",
+		wantUsername: "technician",
+	},
+	{
+		name:         "Neither IP nor username",
+		msg:          "Login Failed",
+		wantIP:       "",
+		wantUsername: "",
+	},
+}
+
+func TestParseLoginMessage(t *testing.T) {
+	for _, tc := range parseLoginMessageTests {
+		if ip, username := parseLoginMessage(tc.msg); ip != tc.wantIP || username != tc.wantUsername {
+			t.Errorf(
+				"%q: parseLoginMessage(%q) = (%q, %q) want: (%q, %q)",
+				tc.name,
+				tc.msg,
+				ip,
+				username,
+				tc.wantIP,
+				tc.wantUsername,
+			)
+		}
+	}
+}
+
+func TestExtractLoginAudit(t *testing.T) {
+	st, err := ParseRawStatus(loadRawStatus(t, s33StatusFixture))
+	if err != nil {
+		t.Fatalf("ParseRawStatus() failed, reason: %s", err)
+	}
+
+	audit := ExtractLoginAudit(st.Logs)
+	if len(audit.Events) != 2 {
+		t.Fatalf("ExtractLoginAudit() len(Events) = %d want: 2", len(audit.Events))
+	}
+	if audit.LatestSuccess == nil || audit.LatestSuccess.SourceIP != "192.168.0.10" {
+		t.Errorf("ExtractLoginAudit() LatestSuccess = %+v want source IP %q", audit.LatestSuccess, "192.168.0.10")
+	}
+	if audit.LatestFailure == nil || audit.LatestFailure.Username != "admin" {
+		t.Errorf("ExtractLoginAudit() LatestFailure = %+v want username %q", audit.LatestFailure, "admin")
+	}
+	if got := audit.Sources["192.168.0.23"]; got.Failures != 1 || got.Successes != 0 {
+		t.Errorf("ExtractLoginAudit() Sources[%q] = %+v want: {Successes:0 Failures:1}", "192.168.0.23", got)
+	}
+}
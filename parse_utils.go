@@ -1,6 +1,7 @@
 package cablemodemutil
 
 import (
+	"errors"
 	"fmt"
 	"strconv"
 	"strings"
@@ -12,25 +13,25 @@ const (
 	eventLogTimestampFormat = "2/1/2006 15:04:05"
 	// System timestamps are in the format "DAY MON DATE HH:MM:SS YYYY".
 	systemTimestampFormat = "Mon Jan 2 15:04:05 2006"
+	// Upstream symbol rates are in Ksym/sec.
+	symbolRateMultiplier = 1000
+	// Width of a DOCSIS SC-QAM upstream channel relative to its symbol
+	// rate, accounting for the 25% roll-off.
+	scQAMUpstreamWidthFactor = 1.25
 )
 
 // Parses the specified string as an uint32 after stripping the suffix if required.
 func parseUint32(str string, hasSuffix bool, suffix string, desc string) (uint32, error) {
 	if hasSuffix {
 		if !strings.HasSuffix(str, suffix) {
-			return 0, fmt.Errorf(
-				"expected %s with %q suffix, but not available in %q",
-				desc,
-				suffix,
-				str,
-			)
+			return 0, newParseError(desc, str, fmt.Errorf("expected %q suffix, but not available", suffix))
 		}
 		str = strings.TrimSuffix(str, suffix)
 	}
 
 	res, err := strconv.ParseUint(str, 10, 32)
 	if err != nil {
-		return 0, fmt.Errorf("parsing %q, unable to convert %q to uint32: %w", desc, str, err)
+		return 0, newParseError(desc, str, fmt.Errorf("unable to convert to uint32: %w", err))
 	}
 	return uint32(res), nil
 }
@@ -39,14 +40,14 @@ func parseUint32(str string, hasSuffix bool, suffix string, desc string) (uint32
 func parseFloat32(str string, hasSuffix bool, suffix string, desc string) (float32, error) {
 	if hasSuffix {
 		if !strings.HasSuffix(str, suffix) {
-			return 0, fmt.Errorf("expected %s with %q suffix, but not available in %q", desc, suffix, str)
+			return 0, newParseError(desc, str, fmt.Errorf("expected %q suffix, but not available", suffix))
 		}
 		str = strings.TrimSuffix(str, suffix)
 	}
 
 	res, err := strconv.ParseFloat(str, 32)
 	if err != nil {
-		return 0, fmt.Errorf("parsing %q, unable to convert %q to float32: %w", desc, str, err)
+		return 0, newParseError(desc, str, fmt.Errorf("unable to convert to float32: %w", err))
 	}
 	return float32(res), nil
 }
@@ -76,6 +77,43 @@ func parseChannelIDStr(str string, desc string) (uint32, error) {
 	return parseUint32(str, false, "", desc)
 }
 
+// Parses the channel type based on the specified channel modulation string.
+func parseChannelTypeStr(modulation string) ChannelType {
+	m := strings.ToUpper(modulation)
+	switch {
+	case strings.Contains(m, "OFDMA"):
+		return ChannelTypeOFDMA
+	case strings.Contains(m, "OFDM"):
+		return ChannelTypeOFDM
+	case strings.Contains(m, "QAM"), strings.Contains(m, "TDMA"):
+		return ChannelTypeSCQAM
+	}
+	return ChannelTypeUnknown
+}
+
+// Returns the width in Hz of an SC-QAM upstream channel with the specified
+// symbol rate in Ksym/sec.
+func scQAMUpstreamWidth(symbolRate float32) float32 {
+	return symbolRate * symbolRateMultiplier * scQAMUpstreamWidthFactor
+}
+
+// Parses the specified string as a comma separated list of profile IDs.
+func parseProfileIDsStr(str string, desc string) ([]uint32, error) {
+	if strings.TrimSpace(str) == "" {
+		return nil, nil
+	}
+	ids := strings.Split(str, ",")
+	result := make([]uint32, len(ids))
+	for i, id := range ids {
+		var err error
+		result[i], err = parseUint32(strings.TrimSpace(id), false, "", desc)
+		if err != nil {
+			return nil, err
+		}
+	}
+	return result, nil
+}
+
 // Parses the log timestamp from the specified date and time string values.
 func parseLogTimestamp(dateStr string, timeStr string) (time.Time, error) {
 	timestamp := fmt.Sprintf("%s %s", dateStr, timeStr)
@@ -86,7 +124,7 @@ func parseLogTimestamp(dateStr string, timeStr string) (time.Time, error) {
 	}
 	t, err := time.ParseInLocation(eventLogTimestampFormat, timestamp, loc)
 	if err != nil {
-		return time.Time{}, fmt.Errorf("error parsing timestamp %q, reason: %w", timestamp, err)
+		return time.Time{}, newParseError("Log Timestamp", timestamp, err)
 	}
 	return t, nil
 }
@@ -99,7 +137,7 @@ func parseSystemTimestampStr(timestamp string, desc string) (time.Time, error) {
 	}
 	t, err := time.ParseInLocation(systemTimestampFormat, timestamp, loc)
 	if err != nil {
-		return time.Time{}, fmt.Errorf("error parsing %s timestamp %q, reason: %w", desc, timestamp, err)
+		return time.Time{}, newParseError(desc, timestamp, err)
 	}
 	return t, nil
 }
@@ -113,26 +151,26 @@ func parseDurationStr(str string, desc string) (time.Duration, error) {
 	if len(components) > 1 {
 		d, err := strconv.ParseUint(components[0], 10, 32)
 		if err != nil {
-			return 0, fmt.Errorf("unable to parse days in the specified %s timestamp %q, reason: %w", desc, str, err)
+			return 0, newParseError(desc, str, fmt.Errorf("unable to parse days, reason: %w", err))
 		}
 		days = time.Duration(d)
 		nextIndex = 1
 	}
 	hoursMinsSecs := strings.Split(components[nextIndex], ":")
 	if len(hoursMinsSecs) != 3 {
-		return 0, fmt.Errorf("unable to split hours:mins:secs in the specified %s timestamp %q", desc, str)
+		return 0, newParseError(desc, str, fmt.Errorf("unable to split hours:mins:secs"))
 	}
 	hours, err := parseTimeElementWithSuffix(hoursMinsSecs[0], "h")
 	if err != nil {
-		return 0, fmt.Errorf("unable to parse hours in the specified %s timestamp %q, reason: %w", desc, str, err)
+		return 0, newParseError(desc, str, fmt.Errorf("unable to parse hours, reason: %w", err))
 	}
 	mins, err := parseTimeElementWithSuffix(hoursMinsSecs[1], "m")
 	if err != nil {
-		return 0, fmt.Errorf("unable to parse hours in the specified %s timestamp %q, reason: %w", desc, str, err)
+		return 0, newParseError(desc, str, fmt.Errorf("unable to parse minutes, reason: %w", err))
 	}
 	secs, err := parseTimeElementWithSuffix(hoursMinsSecs[2], "s")
 	if err != nil {
-		return 0, fmt.Errorf("unable to parse hours in the specified %s timestamp %q, reason: %w", desc, str, err)
+		return 0, newParseError(desc, str, fmt.Errorf("unable to parse seconds, reason: %w", err))
 	}
 	res := days * 24 * time.Hour
 	res += time.Duration(hours) * time.Hour
@@ -155,22 +193,113 @@ func parseTimeElementWithSuffix(str string, suffix string) (uint32, error) {
 
 	num, err := strconv.ParseUint(components[0], 10, 32)
 	if err != nil {
-		return 0, fmt.Errorf("unable to parse component %q as a uint", num)
+		return 0, fmt.Errorf("unable to parse component %q as a uint, reason: %w", components[0], err)
 	}
 	return uint32(num), nil
 }
 
+// Parses the log priority string (Eg. "Critical (3)") into the DOCSIS event
+// priority.
+func parseLogPriorityStr(str string) (LogPriority, error) {
+	start := strings.LastIndex(str, "(")
+	end := strings.LastIndex(str, ")")
+	if start < 0 || end < start {
+		return LogPriorityUnknown, newParseError("Log Priority", str, errors.New("expected the priority within parentheses"))
+	}
+	priority, err := parseUint32(str[start+1:end], false, "", "Log Priority")
+	if err != nil {
+		return LogPriorityUnknown, err
+	}
+	return LogPriority(priority), nil
+}
+
 // Parses the specified log entry string.
 func parseLogEntry(str string) string {
 	// Just replace two spaces with one (seen commonly with login log entries).
 	return strings.ReplaceAll(str, "  ", " ")
 }
 
+// Substrings (in lower case) in the log message identifying each of the known
+// kinds of events, in the order of precedence.
+// nolint:gochecknoglobals
+var logEventMatchers = []struct {
+	event      LogEventType
+	substrings []string
+}{
+	{LogEventRangingNoResponse, []string{"no ranging response received", "t3 time-out"}},
+	{LogEventRangingT4Timeout, []string{"t4 time-out", "t4 timeout"}},
+	{LogEventPowerExceedsTopOfDRW, []string{"top of the drw"}},
+	{LogEventDynamicRangeWindowViolation, []string{"dynamic range window violation"}},
+	{LogEventSyncFailure, []string{"timing synchronization failure"}},
+}
+
+// Parses the kind of event from the specified log message.
+func parseLogEventStr(msg string) LogEventType {
+	m := strings.ToLower(msg)
+	for _, matcher := range logEventMatchers {
+		for _, sub := range matcher.substrings {
+			if strings.Contains(m, sub) {
+				return matcher.event
+			}
+		}
+	}
+
+	if strings.Contains(m, "login") || strings.Contains(m, "logged in") {
+		if strings.Contains(m, "fail") || strings.Contains(m, "invalid") || strings.Contains(m, "incorrect") {
+			return LogEventLoginFailure
+		}
+		if strings.Contains(m, "success") || strings.Contains(m, "logged in") {
+			return LogEventLoginSuccess
+		}
+	}
+	return LogEventUnknown
+}
+
+// Parses the log message and the trailing key-value attributes from the
+// specified log string.
+// Eg. "No Ranging Response received - T3 time-out;CM-MAC=aa:bb:cc:dd:ee:ff;CMTS-MAC=00:11:22:33:44:55;"
+func parseLogAttributesStr(str string) (string, map[string]string) {
+	parts := strings.Split(str, ";")
+	msgParts := parts
+	var attrs map[string]string
+	for i, part := range parts {
+		kv := strings.SplitN(part, "=", 2)
+		if len(kv) != 2 || !isLogAttributeKey(kv[0]) {
+			continue
+		}
+		if attrs == nil {
+			attrs = make(map[string]string)
+			msgParts = parts[:i]
+		}
+		attrs[kv[0]] = kv[1]
+	}
+	return strings.TrimSpace(strings.Join(msgParts, ";")), attrs
+}
+
+// Returns true if the specified string is a valid log attribute key, i.e.
+// made up of only upper case letters, digits and hyphens.
+func isLogAttributeKey(str string) bool {
+	if str == "" {
+		return false
+	}
+	for _, c := range str {
+		if (c < 'A' || c > 'Z') && (c < '0' || c > '9') && c != '-' {
+			return false
+		}
+	}
+	return true
+}
+
 // Parses the value of the specified key as a string in the specified status information.
 func parseString(data actionResponseBody, key string, desc string) (string, error) {
 	s, ok := data[key].(string)
 	if !ok {
-		return "", fmt.Errorf("unable to find key %q while parsing %q.\ndata=%v", key, desc, data)
+		return "", &ParseError{
+			Desc:  desc,
+			Key:   key,
+			Value: fmt.Sprintf("%v", data[key]),
+			Err:   fmt.Errorf("unable to find string value for the key.\ndata=%v", data),
+		}
 	}
 	return s, nil
 }
@@ -190,7 +319,8 @@ func parseFreq(data actionResponseBody, key string, hasHzSuffix bool, desc strin
 	if err != nil {
 		return 0, err
 	}
-	return parseFreqStr(s, hasHzSuffix, desc)
+	res, err := parseFreqStr(s, hasHzSuffix, desc)
+	return res, withParseKey(err, key)
 }
 
 // Parses the value of the specified key as a signal power floating point value in the specified status information.
@@ -199,7 +329,8 @@ func parseSignalPower(data actionResponseBody, key string, hasDBMVSuffix bool, d
 	if err != nil {
 		return 0, err
 	}
-	return parseSignalPowerStr(s, hasDBMVSuffix, desc)
+	res, err := parseSignalPowerStr(s, hasDBMVSuffix, desc)
+	return res, withParseKey(err, key)
 }
 
 // Parses the value of the specified key as a signal SNR floating point value in the specified status information.
@@ -208,7 +339,8 @@ func parseSignalSNR(data actionResponseBody, key string, hasDBSuffix bool, desc
 	if err != nil {
 		return 0, err
 	}
-	return parseSignalSNRStr(s, hasDBSuffix, desc)
+	res, err := parseSignalSNRStr(s, hasDBSuffix, desc)
+	return res, withParseKey(err, key)
 }
 
 // Parses the value of the specified key as a channel ID integer value in the specified status information.
@@ -217,7 +349,8 @@ func parseChannelID(data actionResponseBody, key string, desc string) (uint32, e
 	if err != nil {
 		return 0, err
 	}
-	return parseChannelIDStr(s, desc)
+	res, err := parseChannelIDStr(s, desc)
+	return res, withParseKey(err, key)
 }
 
 // Parses the value of the specified key as a system timestamp in the specified status information.
@@ -226,7 +359,8 @@ func parseSystemTimestamp(data actionResponseBody, key string, desc string) (tim
 	if err != nil {
 		return time.Time{}, err
 	}
-	return parseSystemTimestampStr(s, desc)
+	res, err := parseSystemTimestampStr(s, desc)
+	return res, withParseKey(err, key)
 }
 
 // Parses the value of the specified key as a time duration in the specified status information.
@@ -235,5 +369,6 @@ func parseDuration(data actionResponseBody, key string, desc string) (time.Durat
 	if err != nil {
 		return 0, err
 	}
-	return parseDurationStr(s, desc)
+	res, err := parseDurationStr(s, desc)
+	return res, withParseKey(err, key)
 }
@@ -81,3 +81,78 @@ func TestParseUint32(t *testing.T) {
 		}
 	}
 }
+
+var parseLogEventStrTests = []struct {
+	name string
+	msg  string
+	want LogEventType
+}{
+	{
+		name: "T3 time-out",
+		msg:  "No Ranging Response received - T3 time-out",
+		want: LogEventRangingNoResponse,
+	},
+	{
+		name: "Power exceeds top of DRW",
+		msg:  "RNG-RSP CCAP Commanded Power Exceeds Value Corresponding to the Top of the DRW",
+		want: LogEventPowerExceedsTopOfDRW,
+	},
+	{
+		name: "Dynamic range window violation",
+		msg:  "Dynamic Range Window violation",
+		want: LogEventDynamicRangeWindowViolation,
+	},
+	{
+		name: "Successful login",
+		msg:  "Login Successful from 192.168.0.10 username admin",
+		want: LogEventLoginSuccess,
+	},
+	{
+		name: "Failed login",
+		msg:  "Login Failed from 192.168.0.23 username admin",
+		want: LogEventLoginFailure,
+	},
+	{
+		name: "Unknown event",
+		msg:  "Honoring MDD; IP provisioning mode = IPv6",
+		want: LogEventUnknown,
+	},
+}
+
+func TestParseLogEventStr(t *testing.T) {
+	for _, tc := range parseLogEventStrTests {
+		if got := parseLogEventStr(tc.msg); got != tc.want {
+			t.Errorf("%q: parseLogEventStr(%q) = %s want: %s", tc.name, tc.msg, got, tc.want)
+		}
+	}
+}
+
+func TestParseLogAttributesStr(t *testing.T) {
+	str := "No Ranging Response received - T3 time-out;CM-MAC=aa:bb:cc:dd:ee:ff;CMTS-MAC=00:11:22:33:44:55;" +
+		"CM-QOS=1.1;CM-VER=3.1;"
+	wantMsg := "No Ranging Response received - T3 time-out"
+	wantAttrs := map[string]string{
+		"CM-MAC":   "aa:bb:cc:dd:ee:ff",
+		"CMTS-MAC": "00:11:22:33:44:55",
+		"CM-QOS":   "1.1",
+		"CM-VER":   "3.1",
+	}
+
+	msg, attrs := parseLogAttributesStr(str)
+	if msg != wantMsg {
+		t.Errorf("parseLogAttributesStr(%q) message = %q want: %q", str, msg, wantMsg)
+	}
+	if len(attrs) != len(wantAttrs) {
+		t.Errorf("parseLogAttributesStr(%q) attributes = %v want: %v", str, attrs, wantAttrs)
+	}
+	for k, v := range wantAttrs {
+		if attrs[k] != v {
+			t.Errorf("parseLogAttributesStr(%q) attributes[%q] = %q want: %q", str, k, attrs[k], v)
+		}
+	}
+
+	str = "Honoring MDD; IP provisioning mode = IPv6"
+	if msg, attrs = parseLogAttributesStr(str); msg != str || attrs != nil {
+		t.Errorf("parseLogAttributesStr(%q) = (%q, %v) want: (%q, nil)", str, msg, attrs, str)
+	}
+}
@@ -6,58 +6,103 @@ import (
 	"strings"
 )
 
+const (
+	cmtsMACAttribute   = "CMTS-MAC"
+	cmQOSAttribute     = "CM-QOS"
+	cmVersionAttribute = "CM-VER"
+)
+
 type actionResponseBody map[string]interface{}
 
 // ParseRawStatus parses the raw status returned by the cable modem into the structured cable modem status.
 func ParseRawStatus(status CableModemRawStatus) (*CableModemStatus, error) {
-	err := validateSubResponses(status)
+	return ParseRawStatusWithOptions(status, nil)
+}
+
+// ParseRawStatusWithOptions parses the raw status returned by the cable modem
+// into the structured cable modem status, limited to the sections specified
+// in the options. The rest of the sections are neither validated nor
+// populated.
+// nolint:cyclop
+func ParseRawStatusWithOptions(status CableModemRawStatus, opts *StatusOptions) (*CableModemStatus, error) {
+	sections := opts.sections()
+	err := validateSubResponses(status, statusSubCommandsFor(sections))
 	if err != nil {
 		return nil, fmt.Errorf("invalid status response. reason: %w", err)
 	}
 
-	result := CableModemStatus{}
-	err = populateDeviceInfo(status, &result.Info)
-	if err != nil {
-		return nil, err
+	result := CableModemStatus{Sections: sections}
+	if sections.Has(SectionInfo) {
+		err = populateDeviceInfo(status, &result.Info)
+		if err != nil {
+			return nil, err
+		}
 	}
-	err = populateDeviceSettings(status, &result.Settings)
-	if err != nil {
-		return nil, err
+	if sections.Has(SectionSettings) {
+		err = populateDeviceSettings(status, &result.Settings)
+		if err != nil {
+			return nil, err
+		}
 	}
-	err = populateAuthSettings(status, &result.Auth)
-	if err != nil {
-		return nil, err
+	if sections.Has(SectionAuth) {
+		err = populateAuthSettings(status, &result.Auth)
+		if err != nil {
+			return nil, err
+		}
 	}
-	err = populateSoftwareStatus(status, &result.Software)
-	if err != nil {
-		return nil, err
+	if sections.Has(SectionSoftware) {
+		err = populateSoftwareStatus(status, &result.Software)
+		if err != nil {
+			return nil, err
+		}
 	}
-	err = populateStartupStatus(status, &result.Startup)
-	if err != nil {
-		return nil, err
+	if sections.Has(SectionStartup) {
+		err = populateStartupStatus(status, &result.Startup)
+		if err != nil {
+			return nil, err
+		}
 	}
-	err = populateConnectionStatus(status, &result.Connection)
+	err = populateConnectionStatus(status, sections, &result.Connection)
 	if err != nil {
 		return nil, err
 	}
-	result.Logs, err = populateLogEntries(status)
-	if err != nil {
-		return nil, err
+	if sections.Has(SectionLogs) {
+		result.Logs, err = populateLogEntries(status)
+		if err != nil {
+			return nil, err
+		}
+		result.LogEventCounts = countLogEvents(result.Logs)
+		populateCMTSInfo(result.Logs, &result.Connection)
 	}
 	return &result, nil
 }
 
-func actionResp(resp interface{}) actionResponseBody {
-	body, ok := resp.(map[string]interface{})
+// Retrieves the sub-response for the specified command from the status response.
+func actionResp(status CableModemRawStatus, cmd string) (actionResponseBody, error) {
+	key := actionResponseKey(cmd)
+	val, keyExists := status[key]
+	if !keyExists {
+		return nil, &ResponseError{
+			Action:   cmd,
+			Reason:   fmt.Sprintf("unable to find the response key %q in status response", key),
+			Response: prettyPrintJSON(status),
+		}
+	}
+
+	body, ok := val.(map[string]interface{})
 	if !ok {
-		panic(fmt.Sprintf("Cannot convert given type to actionResponseBody, data:%v", resp))
+		return nil, &ResponseError{
+			Action:   cmd,
+			Reason:   fmt.Sprintf("sub-response for the key %q in status response is not a JSON object", key),
+			Response: prettyPrintJSON(val),
+		}
 	}
-	return actionResponseBody(body)
+	return actionResponseBody(body), nil
 }
 
 // Validates all the sub-responses within the status response were successful and have the expected payload.
-func validateSubResponses(status CableModemRawStatus) error {
-	for _, cmd := range statusSubCommands {
+func validateSubResponses(status CableModemRawStatus, cmds []string) error {
+	for _, cmd := range cmds {
 		err := validateSubResponse(status, cmd)
 		if err != nil {
 			return err
@@ -68,57 +113,57 @@ func validateSubResponses(status CableModemRawStatus) error {
 
 // Validates a specific command's sub-response within the status response.
 func validateSubResponse(status CableModemRawStatus, cmd string) error {
-	key := actionResponseKey(cmd)
-	val, keyExists := status[key]
-	if !keyExists {
-		return fmt.Errorf(
-			"unable to find the response key %q in status response. response: %s",
-			key,
-			prettyPrintJSON(status),
-		)
+	unpacked, err := actionResp(status, cmd)
+	if err != nil {
+		return err
 	}
 
-	unpacked := actionResp(val)
-	key = actionResultKey(cmd)
+	key := actionResultKey(cmd)
 	result, keyExists := unpacked[key].(string)
 	if !keyExists {
-		return fmt.Errorf(
-			"unable to find the result key %q in status response. response: %s",
-			key,
-			prettyPrintJSON(status),
-		)
+		return &ResponseError{
+			Action:   cmd,
+			Reason:   fmt.Sprintf("unable to find the result key %q in status response", key),
+			Response: prettyPrintJSON(unpacked),
+		}
 	}
 
 	if result != "OK" {
-		return fmt.Errorf(
-			"result in unpacked resposne is %q, expected \"OK\".\nunpacked response: %v",
-			result,
-			prettyPrintJSON(unpacked),
-		)
+		return &ResultError{
+			Action:   cmd,
+			Result:   result,
+			Response: prettyPrintJSON(unpacked),
+		}
 	}
 	return nil
 }
 
 // Compare the values for the specified keys and emits a warning message if they differ.
+// Sub-responses which are unavailable are skipped, and keys which are unavailable
+// are compared as empty values.
 func warnIfMismatch(
 	status CableModemRawStatus,
 	desc string,
-	expectedKey string,
+	expectedCmd string,
 	expectedSubKey string,
 	compareAgainst map[string]string,
 ) {
-	expected := actionResp(status[expectedKey])[expectedSubKey]
+	expected := subResponseValue(status, expectedCmd, expectedSubKey)
 
-	for key, subKey := range compareAgainst {
-		actual := actionResp(status[key])[subKey]
+	for cmd, subKey := range compareAgainst {
+		if _, keyExists := status[actionResponseKey(cmd)]; !keyExists {
+			// The sub-response was not requested.
+			continue
+		}
+		actual := subResponseValue(status, cmd, subKey)
 		if expected != actual {
 			log.Printf(
 				"Warning: %s information mismatch between %q[%q]=%q and %q[%q]=%q",
 				desc,
-				expectedKey,
+				actionResponseKey(expectedCmd),
 				expectedSubKey,
 				expected,
-				key,
+				actionResponseKey(cmd),
 				subKey,
 				actual,
 			)
@@ -126,10 +171,26 @@ func warnIfMismatch(
 	}
 }
 
+// Returns the string representation of the value for the specified key in
+// the specified command's sub-response, or an empty string if unavailable.
+func subResponseValue(status CableModemRawStatus, cmd string, key string) string {
+	body, err := actionResp(status, cmd)
+	if err != nil {
+		return ""
+	}
+	val, keyExists := body[key]
+	if !keyExists {
+		return ""
+	}
+	return fmt.Sprintf("%v", val)
+}
+
 // Populates cable modem device information.
 func populateDeviceInfo(status CableModemRawStatus, result *DeviceInfo) error {
-	var err error
-	data := actionResp(status["GetArrisRegisterInfoResponse"])
+	data, err := actionResp(status, "GetArrisRegisterInfo")
+	if err != nil {
+		return err
+	}
 
 	result.Model, err = parseString(data, "ModelName", "Model Name")
 	if err != nil {
@@ -147,19 +208,19 @@ func populateDeviceInfo(status CableModemRawStatus, result *DeviceInfo) error {
 	warnIfMismatch(
 		status,
 		"Serial Number",
-		"GetArrisRegisterInfoResponse",
+		"GetArrisRegisterInfo",
 		"SerialNumber",
 		map[string]string{
-			"GetCustomerStatusSoftwareResponse": "StatusSoftwareSerialNum",
+			"GetCustomerStatusSoftware": "StatusSoftwareSerialNum",
 		},
 	)
 	warnIfMismatch(
 		status,
 		"MAC Address",
-		"GetArrisRegisterInfoResponse",
+		"GetArrisRegisterInfo",
 		"MacAddress",
 		map[string]string{
-			"GetCustomerStatusSoftwareResponse": "StatusSoftwareMac",
+			"GetCustomerStatusSoftware": "StatusSoftwareMac",
 		},
 	)
 	return nil
@@ -167,9 +228,14 @@ func populateDeviceInfo(status CableModemRawStatus, result *DeviceInfo) error {
 
 // Populates cable modem device settings.
 func populateDeviceSettings(status CableModemRawStatus, result *DeviceSettings) error {
-	var err error
-	conf := actionResp(status["GetArrisConfigurationInfoResponse"])
-	reg := actionResp(status["GetArrisRegisterStatusResponse"])
+	conf, err := actionResp(status, "GetArrisConfigurationInfo")
+	if err != nil {
+		return err
+	}
+	reg, err := actionResp(status, "GetArrisRegisterStatus")
+	if err != nil {
+		return err
+	}
 
 	result.FrontPanelLightsOn, err = parseBool(conf, "LedStatus", "1", "LED Status")
 	if err != nil {
@@ -193,8 +259,10 @@ func populateDeviceSettings(status CableModemRawStatus, result *DeviceSettings)
 
 // Populates cable modem auth settings.
 func populateAuthSettings(status CableModemRawStatus, result *AuthSettings) error {
-	var err error
-	acc := actionResp(status["GetCustomerStatusSecAccountResponse"])
+	acc, err := actionResp(status, "GetCustomerStatusSecAccount")
+	if err != nil {
+		return err
+	}
 
 	result.CurrentLogin, err = parseString(acc, "CurrentLogin", "Current Login")
 	if err != nil {
@@ -222,8 +290,10 @@ func populateAuthSettings(status CableModemRawStatus, result *AuthSettings) erro
 
 // Populates cable modem software status.
 func populateSoftwareStatus(status CableModemRawStatus, result *SoftwareStatus) error {
-	var err error
-	sw := actionResp(status["GetCustomerStatusSoftwareResponse"])
+	sw, err := actionResp(status, "GetCustomerStatusSoftware")
+	if err != nil {
+		return err
+	}
 	result.FirmwareVersion, err = parseString(sw, "StatusSoftwareSfVer", "Firmware Version")
 	if err != nil {
 		return err
@@ -251,8 +321,10 @@ func populateSoftwareStatus(status CableModemRawStatus, result *SoftwareStatus)
 // Populates cable modem startup status.
 // nolint:funlen
 func populateStartupStatus(status CableModemRawStatus, result *StartupStatus) error {
-	var err error
-	startup := actionResp(status["GetCustomerStatusStartupSequenceResponse"])
+	startup, err := actionResp(status, "GetCustomerStatusStartupSequence")
+	if err != nil {
+		return err
+	}
 
 	result.Boot.Status, err = parseBool(startup, "CustomerConnBootStatus", "OK", "Boot Status")
 	if err != nil {
@@ -336,12 +408,53 @@ func populateStartupStatus(status CableModemRawStatus, result *StartupStatus) er
 	return nil
 }
 
-// populates cable modem connection status.
-// nolint:funlen
-func populateConnectionStatus(status CableModemRawStatus, result *ConnectionStatus) error {
+// Populates the specified sections of the cable modem connection status.
+func populateConnectionStatus(status CableModemRawStatus, sections StatusSection, result *ConnectionStatus) error {
 	var err error
-	conn := actionResp(status["GetCustomerStatusConnectionInfoResponse"])
-	dev := actionResp(status["GetArrisDeviceStatusResponse"])
+	if sections.Has(SectionConnection) {
+		err = populateConnectionSummary(status, result)
+		if err != nil {
+			return err
+		}
+	}
+	if sections.Has(SectionDownstreamChannels) {
+		result.Downstream.Channels, err = populateDownstreamChannels(status)
+		if err != nil {
+			return err
+		}
+	}
+	if sections.Has(SectionUpstreamChannels) {
+		result.Upstream.Channels, err = populateUpstreamChannels(status)
+		if err != nil {
+			return err
+		}
+	}
+	if sections.Has(SectionOFDMDownstreamChannels) {
+		result.Downstream.OFDMChannels, err = populateOFDMDownstreamChannels(status)
+		if err != nil {
+			return err
+		}
+	}
+	if sections.Has(SectionOFDMAUpstreamChannels) {
+		result.Upstream.OFDMAChannels, err = populateOFDMAUpstreamChannels(status)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Populates cable modem connection status excluding the channel information.
+// nolint:funlen
+func populateConnectionSummary(status CableModemRawStatus, result *ConnectionStatus) error {
+	conn, err := actionResp(status, "GetCustomerStatusConnectionInfo")
+	if err != nil {
+		return err
+	}
+	dev, err := actionResp(status, "GetArrisDeviceStatus")
+	if err != nil {
+		return err
+	}
 
 	result.SystemTime, err = parseSystemTimestamp(conn, "CustomerCurSystemTime", "Current System Time")
 	if err != nil {
@@ -382,9 +495,14 @@ func populateConnectionStatus(status CableModemRawStatus, result *ConnectionStat
 }
 
 func populateDownstreamConnectionStatus(status CableModemRawStatus, result *DownstreamConnectionStatus) error {
-	var err error
-	dev := actionResp(status["GetArrisDeviceStatusResponse"])
-	config := actionResp(status["GetArrisConfigurationInfoResponse"])
+	dev, err := actionResp(status, "GetArrisDeviceStatus")
+	if err != nil {
+		return err
+	}
+	config, err := actionResp(status, "GetArrisConfigurationInfo")
+	if err != nil {
+		return err
+	}
 
 	result.Plan, err = parseString(config, "DownstreamPlan", "Downstream Plan")
 	if err != nil {
@@ -407,10 +525,6 @@ func populateDownstreamConnectionStatus(status CableModemRawStatus, result *Down
 	if err != nil {
 		return err
 	}
-	result.Channels, err = populateDownstreamChannels(status)
-	if err != nil {
-		return err
-	}
 
 	// TODO: Verify downstream frequency is the same in all three places below..
 	// GetArrisConfigurationInfoResponse.DownstreamFrequency (no HZ suffix in string)
@@ -421,14 +535,12 @@ func populateDownstreamConnectionStatus(status CableModemRawStatus, result *Down
 }
 
 func populateUpstreamConnectionStatus(status CableModemRawStatus, result *UpstreamConnectionStatus) error {
-	var err error
-	config := actionResp(status["GetArrisConfigurationInfoResponse"])
-
-	result.ChannelID, err = parseChannelID(config, "UpstreamChannelId", "Upstream Channel ID")
+	config, err := actionResp(status, "GetArrisConfigurationInfo")
 	if err != nil {
 		return err
 	}
-	result.Channels, err = populateUpstreamChannels(status)
+
+	result.ChannelID, err = parseChannelID(config, "UpstreamChannelId", "Upstream Channel ID")
 	if err != nil {
 		return err
 	}
@@ -438,9 +550,12 @@ func populateUpstreamConnectionStatus(status CableModemRawStatus, result *Upstre
 
 // Populates cable modem downstream channel information.
 func populateDownstreamChannels(status CableModemRawStatus) ([]DownstreamChannelInfo, error) {
-	var err error
-	dsInfo := actionResp(status["GetCustomerStatusDownstreamChannelInfoResponse"])
-	squashedRows, err := parseString(dsInfo, "CustomerConnDownstreamChannel", "Downstream Channel info")
+	const dsKey = "CustomerConnDownstreamChannel"
+	dsInfo, err := actionResp(status, "GetCustomerStatusDownstreamChannelInfo")
+	if err != nil {
+		return nil, err
+	}
+	squashedRows, err := parseString(dsInfo, dsKey, "Downstream Channel info")
 	if err != nil {
 		return nil, err
 	}
@@ -454,38 +569,40 @@ func populateDownstreamChannels(status CableModemRawStatus) ([]DownstreamChannel
 		// The columns are:
 		// Row ID, Lock Status, Modulation, Channel ID, Frequency, Power, SNR, Corrected Err, Uncorrected Err, Blank
 		if len(cols) != 10 {
-			return nil, fmt.Errorf(
-				"expected 10 columns in a downstream channel, actual %d row=%q",
-				len(cols),
-				row,
-			)
+			return nil, &ParseError{
+				Desc:  "Downstream Channel info",
+				Key:   dsKey,
+				Value: row,
+				Err:   fmt.Errorf("expected 10 columns in a downstream channel, actual %d", len(cols)),
+			}
 		}
 
 		result[i].Locked = cols[1] == "LOCKED"
 		result[i].Modulation = cols[2]
+		result[i].Type = parseChannelTypeStr(cols[2])
 		result[i].ChannelID, err = parseChannelIDStr(cols[3], "Downstream Channel ID")
 		if err != nil {
-			return nil, err
+			return nil, withParseKey(err, dsKey)
 		}
 		result[i].FrequencyHZ, err = parseFreqStr(cols[4], false, "Downstream Channel Frequency")
 		if err != nil {
-			return nil, err
+			return nil, withParseKey(err, dsKey)
 		}
 		result[i].SignalPowerDBMV, err = parseSignalPowerStr(cols[5], false, "Downstream Channel Signal Power")
 		if err != nil {
-			return nil, err
+			return nil, withParseKey(err, dsKey)
 		}
 		result[i].SignalSNRMERDB, err = parseSignalSNRStr(cols[6], false, "Downstream Channel Signal SNR/MER")
 		if err != nil {
-			return nil, err
+			return nil, withParseKey(err, dsKey)
 		}
 		result[i].CorrectedErrors, err = parseSignalErrorsStr(cols[7], "Downstream Channel Signal Corrected Errors")
 		if err != nil {
-			return nil, err
+			return nil, withParseKey(err, dsKey)
 		}
 		result[i].UncorrectedErrors, err = parseSignalErrorsStr(cols[8], "Downstream Channel Signal Uncorrected Errors")
 		if err != nil {
-			return nil, err
+			return nil, withParseKey(err, dsKey)
 		}
 	}
 
@@ -494,9 +611,12 @@ func populateDownstreamChannels(status CableModemRawStatus) ([]DownstreamChannel
 
 // Populates cable modem upstream channel information.
 func populateUpstreamChannels(status CableModemRawStatus) ([]UpstreamChannelInfo, error) {
-	var err error
-	usInfo := actionResp(status["GetCustomerStatusUpstreamChannelInfoResponse"])
-	squashedRows, err := parseString(usInfo, "CustomerConnUpstreamChannel", "Upstream Channel info")
+	const usKey = "CustomerConnUpstreamChannel"
+	usInfo, err := actionResp(status, "GetCustomerStatusUpstreamChannelInfo")
+	if err != nil {
+		return nil, err
+	}
+	squashedRows, err := parseString(usInfo, usKey, "Upstream Channel info")
 	if err != nil {
 		return nil, err
 	}
@@ -510,30 +630,193 @@ func populateUpstreamChannels(status CableModemRawStatus) ([]UpstreamChannelInfo
 		// The columns are:
 		// Row ID, Lock Status, Modulation, Channel ID, Width, Frequency, Power, Blank
 		if len(cols) != 8 {
-			return nil, fmt.Errorf(
-				"expected 8 columns in a upstream channel, actual %d row=%q",
-				len(cols),
-				row,
-			)
+			return nil, &ParseError{
+				Desc:  "Upstream Channel info",
+				Key:   usKey,
+				Value: row,
+				Err:   fmt.Errorf("expected 8 columns in an upstream channel, actual %d", len(cols)),
+			}
 		}
 
 		result[i].Locked = cols[1] == "LOCKED"
 		result[i].Modulation = cols[2]
+		result[i].Type = parseChannelTypeStr(cols[2])
 		result[i].ChannelID, err = parseChannelIDStr(cols[3], "Upstream Channel ID")
 		if err != nil {
-			return nil, err
+			return nil, withParseKey(err, usKey)
 		}
 		result[i].WidthHZ, err = parseFreqStr(cols[4], false, "Upstream Channel Width")
 		if err != nil {
-			return nil, err
+			return nil, withParseKey(err, usKey)
 		}
 		result[i].FrequencyHZ, err = parseFreqStr(cols[5], false, "Upstream Channel Frequency")
 		if err != nil {
-			return nil, err
+			return nil, withParseKey(err, usKey)
 		}
 		result[i].SignalPowerDBMV, err = parseSignalPowerStr(cols[6], false, "Upstream Channel Signal Power")
 		if err != nil {
-			return nil, err
+			return nil, withParseKey(err, usKey)
+		}
+	}
+
+	return result, nil
+}
+
+// Populates cable modem DOCSIS 3.1 OFDM downstream channel information.
+// nolint:funlen,cyclop
+func populateOFDMDownstreamChannels(status CableModemRawStatus) ([]OFDMDownstreamChannelInfo, error) {
+	const dsKey = "CustomerConnDownstreamOFDMChannel"
+	dsInfo, err := actionResp(status, "GetCustomerStatusDownstreamOFDMChannelInfo")
+	if err != nil {
+		return nil, err
+	}
+	squashedRows, err := parseString(dsInfo, dsKey, "Downstream OFDM Channel info")
+	if err != nil {
+		return nil, err
+	}
+	if squashedRows == "" {
+		// No OFDM channels.
+		return nil, nil
+	}
+
+	// Each row is delimited by a '|+|'
+	rows := strings.Split(squashedRows, "|+|")
+	result := make([]OFDMDownstreamChannelInfo, len(rows))
+	for i, row := range rows {
+		// Each column is delimited by a '^'
+		cols := strings.Split(row, "^")
+		// The columns are:
+		// Row ID, Lock Status, Channel ID, FFT Type, Subcarrier Zero Frequency, PLC Frequency,
+		// First Active Subcarrier, Last Active Subcarrier, Profile IDs, Power, MER Pilot,
+		// MER PLC, MER Data, Corrected Err, Uncorrected Err, Blank
+		if len(cols) != 16 {
+			return nil, &ParseError{
+				Desc:  "Downstream OFDM Channel info",
+				Key:   dsKey,
+				Value: row,
+				Err:   fmt.Errorf("expected 16 columns in a downstream OFDM channel, actual %d", len(cols)),
+			}
+		}
+
+		result[i].Locked = cols[1] == "LOCKED"
+		result[i].ChannelID, err = parseChannelIDStr(cols[2], "Downstream OFDM Channel ID")
+		if err != nil {
+			return nil, withParseKey(err, dsKey)
+		}
+		result[i].FFTType = cols[3]
+		result[i].SubcarrierZeroFrequencyHZ, err = parseFreqStr(
+			cols[4], false, "Downstream OFDM Channel Subcarrier Zero Frequency")
+		if err != nil {
+			return nil, withParseKey(err, dsKey)
+		}
+		result[i].PLCFrequencyHZ, err = parseFreqStr(cols[5], false, "Downstream OFDM Channel PLC Frequency")
+		if err != nil {
+			return nil, withParseKey(err, dsKey)
+		}
+		result[i].FirstActiveSubcarrier, err = parseUint32(
+			cols[6], false, "", "Downstream OFDM Channel First Active Subcarrier")
+		if err != nil {
+			return nil, withParseKey(err, dsKey)
+		}
+		result[i].LastActiveSubcarrier, err = parseUint32(
+			cols[7], false, "", "Downstream OFDM Channel Last Active Subcarrier")
+		if err != nil {
+			return nil, withParseKey(err, dsKey)
+		}
+		result[i].ProfileIDs, err = parseProfileIDsStr(cols[8], "Downstream OFDM Channel Profile IDs")
+		if err != nil {
+			return nil, withParseKey(err, dsKey)
+		}
+		result[i].SignalPowerDBMV, err = parseSignalPowerStr(cols[9], false, "Downstream OFDM Channel Signal Power")
+		if err != nil {
+			return nil, withParseKey(err, dsKey)
+		}
+		result[i].MERPilotDB, err = parseSignalSNRStr(cols[10], false, "Downstream OFDM Channel MER Pilot")
+		if err != nil {
+			return nil, withParseKey(err, dsKey)
+		}
+		result[i].MERPLCDB, err = parseSignalSNRStr(cols[11], false, "Downstream OFDM Channel MER PLC")
+		if err != nil {
+			return nil, withParseKey(err, dsKey)
+		}
+		result[i].MERDataDB, err = parseSignalSNRStr(cols[12], false, "Downstream OFDM Channel MER Data")
+		if err != nil {
+			return nil, withParseKey(err, dsKey)
+		}
+		result[i].CorrectedErrors, err = parseSignalErrorsStr(
+			cols[13], "Downstream OFDM Channel Signal Corrected Errors")
+		if err != nil {
+			return nil, withParseKey(err, dsKey)
+		}
+		result[i].UncorrectedErrors, err = parseSignalErrorsStr(
+			cols[14], "Downstream OFDM Channel Signal Uncorrected Errors")
+		if err != nil {
+			return nil, withParseKey(err, dsKey)
+		}
+	}
+
+	return result, nil
+}
+
+// Populates cable modem DOCSIS 3.1 OFDMA upstream channel information.
+// nolint:funlen
+func populateOFDMAUpstreamChannels(status CableModemRawStatus) ([]OFDMAUpstreamChannelInfo, error) {
+	const usKey = "CustomerConnUpstreamOFDMAChannel"
+	usInfo, err := actionResp(status, "GetCustomerStatusUpstreamOFDMAChannelInfo")
+	if err != nil {
+		return nil, err
+	}
+	squashedRows, err := parseString(usInfo, usKey, "Upstream OFDMA Channel info")
+	if err != nil {
+		return nil, err
+	}
+	if squashedRows == "" {
+		// No OFDMA channels.
+		return nil, nil
+	}
+
+	// Each row is delimited by a '|+|'
+	rows := strings.Split(squashedRows, "|+|")
+	result := make([]OFDMAUpstreamChannelInfo, len(rows))
+	for i, row := range rows {
+		// Each column is delimited by a '^'
+		cols := strings.Split(row, "^")
+		// The columns are:
+		// Row ID, Lock Status, Channel ID, FFT Type, Subcarrier Zero Frequency,
+		// First Active Subcarrier, Last Active Subcarrier, Power, Blank
+		if len(cols) != 9 {
+			return nil, &ParseError{
+				Desc:  "Upstream OFDMA Channel info",
+				Key:   usKey,
+				Value: row,
+				Err:   fmt.Errorf("expected 9 columns in an upstream OFDMA channel, actual %d", len(cols)),
+			}
+		}
+
+		result[i].Locked = cols[1] == "LOCKED"
+		result[i].ChannelID, err = parseChannelIDStr(cols[2], "Upstream OFDMA Channel ID")
+		if err != nil {
+			return nil, withParseKey(err, usKey)
+		}
+		result[i].FFTType = cols[3]
+		result[i].SubcarrierZeroFrequencyHZ, err = parseFreqStr(
+			cols[4], false, "Upstream OFDMA Channel Subcarrier Zero Frequency")
+		if err != nil {
+			return nil, withParseKey(err, usKey)
+		}
+		result[i].FirstActiveSubcarrier, err = parseUint32(
+			cols[5], false, "", "Upstream OFDMA Channel First Active Subcarrier")
+		if err != nil {
+			return nil, withParseKey(err, usKey)
+		}
+		result[i].LastActiveSubcarrier, err = parseUint32(
+			cols[6], false, "", "Upstream OFDMA Channel Last Active Subcarrier")
+		if err != nil {
+			return nil, withParseKey(err, usKey)
+		}
+		result[i].SignalPowerDBMV, err = parseSignalPowerStr(cols[7], false, "Upstream OFDMA Channel Signal Power")
+		if err != nil {
+			return nil, withParseKey(err, usKey)
 		}
 	}
 
@@ -542,9 +825,12 @@ func populateUpstreamChannels(status CableModemRawStatus) ([]UpstreamChannelInfo
 
 // Populates cable modem log entries.
 func populateLogEntries(status CableModemRawStatus) ([]LogEntry, error) {
-	var err error
-	usInfo := actionResp(status["GetCustomerStatusLogResponse"])
-	squashedRows, err := parseString(usInfo, "CustomerStatusLogList", "Log list")
+	const logKey = "CustomerStatusLogList"
+	usInfo, err := actionResp(status, "GetCustomerStatusLog")
+	if err != nil {
+		return nil, err
+	}
+	squashedRows, err := parseString(usInfo, logKey, "Log list")
 	if err != nil {
 		return nil, err
 	}
@@ -558,28 +844,61 @@ func populateLogEntries(status CableModemRawStatus) ([]LogEntry, error) {
 		// The columns are:
 		// 0, Time, Date, 3, Log
 		if len(cols) != 5 {
-			return nil, fmt.Errorf(
-				"expected 5 columns in a log entry, actual %d row=%q",
-				len(cols),
-				row,
-			)
+			return nil, &ParseError{
+				Desc:  "Log list",
+				Key:   logKey,
+				Value: row,
+				Err:   fmt.Errorf("expected 5 columns in a log entry, actual %d", len(cols)),
+			}
 		}
 
 		result[i].Timestamp, err = parseLogTimestamp(cols[2], cols[1])
 		if err != nil {
-			return nil, err
+			return nil, withParseKey(err, logKey)
+		}
+		// The severity and the priority are informational, and some of the
+		// firmware leave them blank. Hence these are left unset if invalid,
+		// instead of failing to parse the whole status.
+		if severity, sErr := parseUint32(cols[0], false, "", "Log Severity"); sErr == nil {
+			result[i].Severity = severity
+		}
+		if priority, pErr := parseUint32(cols[3], false, "", "Log Priority"); pErr == nil {
+			result[i].Priority = LogPriority(priority)
 		}
 		result[i].Log = parseLogEntry(cols[4])
+		result[i].Message, result[i].Attributes = parseLogAttributesStr(result[i].Log)
+		result[i].Event = parseLogEventStr(result[i].Message)
 	}
 
-	// TODO:
-	// 1. Count number of success/failed login attempts.
-	//		  Expose timestamps and IPs of successful and failed login attempts.
-	// 2. Count other kinds of errors (three known categories so far)
-	//        STARTED_UNICAST_MAINTENANCE_RANGING_NO_RESPONSE_RECEIVED
-	//        RNG_RSP_CCAP_COMMAND_POWER_EXCEEDS_TOP_OF_DRW
-	//        DYNAMIC_RANGE_WINDOW_VIOLATION
-	// 3. Parse CMTS MAC from the latest log entry with the info (if available).
-
 	return result, nil
 }
+
+// Populates the CMTS information in the connection status from the newest
+// log entry carrying the CMTS MAC address.
+func populateCMTSInfo(logs []LogEntry, result *ConnectionStatus) {
+	var newest *LogEntry
+	for i := range logs {
+		l := &logs[i]
+		if l.Attributes[cmtsMACAttribute] == "" {
+			continue
+		}
+		if newest == nil || !l.Timestamp.Before(newest.Timestamp) {
+			newest = l
+		}
+	}
+	if newest == nil {
+		return
+	}
+	result.CMTSMACAddress = newest.Attributes[cmtsMACAttribute]
+	result.CMQOS = newest.Attributes[cmQOSAttribute]
+	result.CMVersion = newest.Attributes[cmVersionAttribute]
+}
+
+// Counts the number of log entries for each kind of event.
+func countLogEvents(logs []LogEntry) map[LogEventType]uint32 {
+	result := make(map[LogEventType]uint32)
+	for _, l := range logs {
+		result[l.Event]++
+	}
+	return result
+}
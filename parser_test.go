@@ -0,0 +1,206 @@
+package cablemodemutil
+
+import (
+	"encoding/json"
+	"errors"
+	"os"
+	"testing"
+)
+
+const s33StatusFixture = "testdata/s33_status.json"
+
+// Loads the specified raw status fixture.
+func loadRawStatus(t testing.TB, path string) CableModemRawStatus {
+	t.Helper()
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("unable to read fixture %q, reason: %s", path, err)
+	}
+	var raw CableModemRawStatus
+	err = json.Unmarshal(data, &raw)
+	if err != nil {
+		t.Fatalf("unable to decode fixture %q, reason: %s", path, err)
+	}
+	return raw
+}
+
+func TestParseRawStatus(t *testing.T) {
+	st, err := ParseRawStatus(loadRawStatus(t, s33StatusFixture))
+	if err != nil {
+		t.Fatalf("ParseRawStatus() failed, reason: %s", err)
+	}
+	if st.Info.Model != "S33" {
+		t.Errorf("ParseRawStatus() Info.Model = %q want: %q", st.Info.Model, "S33")
+	}
+	if len(st.Connection.Downstream.Channels) != 4 {
+		t.Errorf("ParseRawStatus() len(Downstream.Channels) = %d want: 4", len(st.Connection.Downstream.Channels))
+	}
+	if len(st.Connection.Upstream.Channels) != 3 {
+		t.Errorf("ParseRawStatus() len(Upstream.Channels) = %d want: 3", len(st.Connection.Upstream.Channels))
+	}
+	if len(st.Logs) != 5 {
+		t.Errorf("ParseRawStatus() len(Logs) = %d want: 5", len(st.Logs))
+	}
+	if got := st.Connection.Downstream.Channels[3].Type; got != ChannelTypeOFDM {
+		t.Errorf("ParseRawStatus() Downstream.Channels[3].Type = %s want: %s", got, ChannelTypeOFDM)
+	}
+	if got := st.Connection.Upstream.Channels[0].Type; got != ChannelTypeSCQAM {
+		t.Errorf("ParseRawStatus() Upstream.Channels[0].Type = %s want: %s", got, ChannelTypeSCQAM)
+	}
+	if got := st.Connection.CMTSMACAddress; got != "00:11:22:33:44:66" {
+		t.Errorf("ParseRawStatus() Connection.CMTSMACAddress = %q want: %q", got, "00:11:22:33:44:66")
+	}
+	if got := st.LogEventCounts[LogEventRangingNoResponse]; got != 1 {
+		t.Errorf("ParseRawStatus() LogEventCounts[%s] = %d want: 1", LogEventRangingNoResponse, got)
+	}
+}
+
+func TestParseRawStatusOFDMChannels(t *testing.T) {
+	sections := SectionOFDMDownstreamChannels | SectionOFDMAUpstreamChannels
+	st, err := ParseRawStatusWithOptions(
+		loadRawStatus(t, "testdata/s33_ofdm_status.json"),
+		&StatusOptions{Sections: sections},
+	)
+	if err != nil {
+		t.Fatalf("ParseRawStatusWithOptions() failed, reason: %s", err)
+	}
+
+	ds := st.Connection.Downstream.OFDMChannels
+	if len(ds) != 2 {
+		t.Fatalf("ParseRawStatusWithOptions() len(Downstream.OFDMChannels) = %d want: 2", len(ds))
+	}
+	if ds[0].ChannelID != 33 || ds[0].PLCFrequencyHZ != 957000000 || len(ds[0].ProfileIDs) != 4 {
+		t.Errorf("ParseRawStatusWithOptions() Downstream.OFDMChannels[0] = %+v", ds[0])
+	}
+	if ds[1].MERDataDB != 40.1 || ds[1].UncorrectedErrors != 2 {
+		t.Errorf("ParseRawStatusWithOptions() Downstream.OFDMChannels[1] = %+v", ds[1])
+	}
+
+	us := st.Connection.Upstream.OFDMAChannels
+	if len(us) != 1 {
+		t.Fatalf("ParseRawStatusWithOptions() len(Upstream.OFDMAChannels) = %d want: 1", len(us))
+	}
+	if us[0].ChannelID != 5 || us[0].LastActiveSubcarrier != 1969 || us[0].SignalPowerDBMV != 41.0 {
+		t.Errorf("ParseRawStatusWithOptions() Upstream.OFDMAChannels[0] = %+v", us[0])
+	}
+}
+
+var invalidSubResponseTests = []struct {
+	name string
+	cmd  string
+	val  interface{}
+	want error
+}{
+	{
+		name: "Sub-response is a string",
+		cmd:  "GetArrisRegisterInfo",
+		val:  "foo",
+		want: ErrMalformedResponse,
+	},
+	{
+		name: "Sub-response is an array",
+		cmd:  "GetCustomerStatusLog",
+		val:  []interface{}{"foo"},
+		want: ErrMalformedResponse,
+	},
+	{
+		name: "Sub-response result is not OK",
+		cmd:  "GetArrisDeviceStatus",
+		val:  map[string]interface{}{"GetArrisDeviceStatusResult": "ERROR"},
+		want: ErrResultNotOK,
+	},
+	{
+		name: "Field is not a string",
+		cmd:  "GetArrisRegisterInfo",
+		val: map[string]interface{}{
+			"GetArrisRegisterInfoResult": "OK",
+			"ModelName":                  "S33",
+			"SerialNumber":               map[string]interface{}{},
+			"MacAddress":                 "AA:BB:CC:DD:EE:FF",
+		},
+		want: ErrParse,
+	},
+}
+
+func TestParseRawStatusInvalidSubResponse(t *testing.T) {
+	for _, tc := range invalidSubResponseTests {
+		raw := loadRawStatus(t, s33StatusFixture)
+		raw[actionResponseKey(tc.cmd)] = tc.val
+		if _, err := ParseRawStatus(raw); !errors.Is(err, tc.want) {
+			t.Errorf("%q: ParseRawStatus() error = %v want: %v", tc.name, err, tc.want)
+		}
+	}
+}
+
+func TestParseRawStatusLenientLogColumns(t *testing.T) {
+	raw := loadRawStatus(t, s33StatusFixture)
+	raw[actionResponseKey("GetCustomerStatusLog")] = map[string]interface{}{
+		"GetCustomerStatusLogResult": "OK",
+		"CustomerStatusLogList":      "^09:38:30^21/10/2021^^T3 time-out}-{x^09:40:02^21/10/2021^5^Dynamic Range Window violation",
+	}
+
+	st, err := ParseRawStatus(raw)
+	if err != nil {
+		t.Fatalf("ParseRawStatus() failed, reason: %s", err)
+	}
+	for i, l := range st.Logs {
+		if l.Severity != 0 {
+			t.Errorf("ParseRawStatus() Logs[%d].Severity = %d want: 0", i, l.Severity)
+		}
+	}
+	if st.Logs[0].Priority != LogPriorityUnknown || st.Logs[1].Priority != LogPriorityWarning {
+		t.Errorf("ParseRawStatus() Logs priorities = [%s %s] want: [%s %s]",
+			st.Logs[0].Priority, st.Logs[1].Priority, LogPriorityUnknown, LogPriorityWarning)
+	}
+}
+
+func FuzzParseRawStatus(f *testing.F) {
+	data, err := os.ReadFile(s33StatusFixture)
+	if err != nil {
+		f.Fatalf("unable to read fixture %q, reason: %s", s33StatusFixture, err)
+	}
+	f.Add(string(data))
+	f.Add(`{}`)
+	f.Add(`{"GetArrisRegisterInfoResponse": "foo"}`)
+	f.Add(`{"GetArrisRegisterInfoResponse": {"GetArrisRegisterInfoResult": "OK", "SerialNumber": []}}`)
+
+	f.Fuzz(func(t *testing.T, input string) {
+		var raw CableModemRawStatus
+		if err := json.Unmarshal([]byte(input), &raw); err != nil {
+			return
+		}
+		// Must not panic regardless of the input.
+		_, _ = ParseRawStatus(raw)
+	})
+}
+
+func TestParseRawStatusWithSections(t *testing.T) {
+	full := loadRawStatus(t, s33StatusFixture)
+	sections := SectionDownstreamChannels | SectionUpstreamChannels
+	cmds := statusSubCommandsFor(sections)
+
+	raw := make(CableModemRawStatus)
+	for _, cmd := range cmds {
+		raw[actionResponseKey(cmd)] = full[actionResponseKey(cmd)]
+	}
+
+	st, err := ParseRawStatusWithOptions(raw, &StatusOptions{Sections: sections})
+	if err != nil {
+		t.Fatalf("ParseRawStatusWithOptions() failed, reason: %s", err)
+	}
+	if st.Sections != sections {
+		t.Errorf("ParseRawStatusWithOptions() Sections = %b want: %b", st.Sections, sections)
+	}
+	if len(st.Connection.Downstream.Channels) == 0 || len(st.Connection.Upstream.Channels) == 0 {
+		t.Errorf("ParseRawStatusWithOptions() channel information is missing")
+	}
+	if st.Info.Model != "" || len(st.Logs) != 0 {
+		t.Errorf("ParseRawStatusWithOptions() populated sections which were not requested")
+	}
+
+	// The full set of sub-commands must be validated when parsing all the
+	// sections.
+	if _, err := ParseRawStatus(raw); !errors.Is(err, ErrMalformedResponse) {
+		t.Errorf("ParseRawStatus() error = %v want: %v", err, ErrMalformedResponse)
+	}
+}
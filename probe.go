@@ -0,0 +1,304 @@
+package cablemodemutil
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"sort"
+	"strings"
+)
+
+const (
+	// Action describing the device, served by the HNAP1 cable modems
+	// without logging in.
+	deviceSettingsAction = "GetDeviceSettings"
+	// Maximum size of the pages read while probing.
+	maxProbePageSize = 1 << 20
+)
+
+// The pages fetched while probing for the cable modems without HNAP1
+// support, in the order of preference.
+// nolint:gochecknoglobals
+var probePages = []string{"/", arrisConnectionStatusPage, netgearDocsisStatusPage}
+
+// The markers in the pages preceding the model of the cable modem
+// (Eg. `<span id="thisModelNumberIs">SB8200</span>` in the Arris SB
+// series pages).
+// nolint:gochecknoglobals
+var probePageMarkers = []string{`id="thisModelNumberIs"`, `id="ModelName"`}
+
+// ProbeResult contains the cable modem detected by Probe.
+type ProbeResult struct {
+	// Vendor of the cable modem, as reported by the driver.
+	Vendor string
+	// Model of the cable modem.
+	Model string
+	// The protocol the cable modem was detected with.
+	Protocol string
+	// Description of how the model was detected
+	// (Eg. "HNAP GetDeviceSettings over https").
+	DetectedBy string
+	// Retriever using the driver for the detected model, built from the
+	// input supplied to Probe.
+	Retriever *Retriever
+}
+
+// ProbeAttempt contains a fingerprinting method tried by Probe and the
+// reason it failed to detect the cable modem.
+type ProbeAttempt struct {
+	// Description of the fingerprinting method.
+	Method string
+	// The reason for the failure.
+	Err error
+}
+
+// ProbeError is returned when Probe is unable to detect the model of the
+// cable modem, listing all the fingerprinting methods tried.
+type ProbeError struct {
+	// The host name or IP address of the cable modem device.
+	Host string
+	// The fingerprinting methods tried, in order.
+	Attempts []ProbeAttempt
+}
+
+func (e *ProbeError) Error() string {
+	var b strings.Builder
+	if e.reached() {
+		fmt.Fprintf(&b, "unable to detect a supported cable modem at host %q, tried:", e.Host)
+	} else {
+		fmt.Fprintf(&b, "unable to reach the cable modem at host %q, tried:", e.Host)
+	}
+	for _, a := range e.Attempts {
+		fmt.Fprintf(&b, "\n  - %s: %s", a.Method, a.Err)
+	}
+	return b.String()
+}
+
+// Is returns true if the target is ErrUnsupported and at least one of the
+// attempts reached the cable modem.
+func (e *ProbeError) Is(target error) bool {
+	return target == ErrUnsupported && e.reached()
+}
+
+// Unwrap returns the error from the last attempt if none of the attempts
+// reached the cable modem (Eg. the host is down or the connection timed
+// out), nil otherwise.
+func (e *ProbeError) Unwrap() error {
+	if e.reached() || len(e.Attempts) == 0 {
+		return nil
+	}
+	return e.Attempts[len(e.Attempts)-1].Err
+}
+
+// Returns true if at least one of the attempts reached the cable modem.
+func (e *ProbeError) reached() bool {
+	for _, a := range e.Attempts {
+		if !unreachable(a.Err) {
+			return true
+		}
+	}
+	return false
+}
+
+// Probe detects the model of the cable modem at the host specified in the
+// input using unauthenticated requests, and returns it along with a
+// retriever using the driver for the model. The model in the input is
+// ignored. If the protocol is unspecified in the input, "https" and "http"
+// are tried in that order.
+//
+// The cable modem is fingerprinted using the HNAP1 GetDeviceSettings
+// action, followed by the titles, model markers and authentication realms
+// of the well known status pages. Only the registered models are detected.
+//
+// If the model is not detected, returns a ProbeError which matches
+// ErrUnsupported if the cable modem was reachable, or wraps the network
+// error otherwise.
+func Probe(ctx context.Context, input *RetrieverInput) (*ProbeResult, error) {
+	protocols := []string{input.Protocol}
+	if input.Protocol == "" {
+		protocols = []string{"https", "http"}
+	}
+	client := newStdHTTPClient(input)
+	models := probeModels()
+
+	pErr := ProbeError{Host: input.Host}
+	for _, protocol := range protocols {
+		in := *input
+		in.Protocol = protocol
+
+		method := fmt.Sprintf("HNAP %s over %s", deviceSettingsAction, protocol)
+		model, err := probeHNAP(ctx, &in, client, models)
+		if err == nil {
+			return newProbeResult(&in, model, method)
+		}
+		pErr.Attempts = append(pErr.Attempts, ProbeAttempt{Method: method, Err: err})
+		if ctx.Err() != nil {
+			return nil, &pErr
+		}
+		if unreachable(err) {
+			continue
+		}
+
+		for _, page := range probePages {
+			pageURL := fmt.Sprintf("%s://%s%s", protocol, input.Host, page)
+			method = fmt.Sprintf("HTML page %s", pageURL)
+			model, err = probePage(ctx, client, pageURL, models)
+			if err == nil {
+				return newProbeResult(&in, model, method)
+			}
+			pErr.Attempts = append(pErr.Attempts, ProbeAttempt{Method: method, Err: err})
+			if unreachable(err) {
+				break
+			}
+		}
+	}
+	return nil, &pErr
+}
+
+// Returns true if the specified error indicates the cable modem is not
+// reachable using the protocol, and hence the rest of the requests using
+// the protocol are expected to fail too.
+func unreachable(err error) bool {
+	var urlErr *url.Error
+	return errors.As(err, &urlErr)
+}
+
+// Returns the probe result for the specified detected model.
+func newProbeResult(input *RetrieverInput, model string, method string) (*ProbeResult, error) {
+	input.Model = model
+	r, err := NewRetriever(input)
+	if err != nil {
+		return nil, err
+	}
+	return &ProbeResult{
+		Vendor:     r.Driver().Capabilities().Vendor,
+		Model:      model,
+		Protocol:   input.Protocol,
+		DetectedBy: method,
+		Retriever:  r,
+	}, nil
+}
+
+// Returns the registered models, longest first so that the most specific
+// model is matched first.
+func probeModels() []string {
+	models := RegisteredModels()
+	sort.SliceStable(models, func(i, j int) bool {
+		return len(models[i]) > len(models[j])
+	})
+	return models
+}
+
+// Returns the first of the specified models present as a whole word in any
+// of the specified texts (case insensitive).
+func matchModel(models []string, texts ...string) (string, bool) {
+	isWordChar := func(c byte) bool {
+		return c >= '0' && c <= '9' || c >= 'A' && c <= 'Z'
+	}
+	for _, text := range texts {
+		text = strings.ToUpper(text)
+		for _, model := range models {
+			for i := 0; ; {
+				j := strings.Index(text[i:], model)
+				if j < 0 {
+					break
+				}
+				start, end := i+j, i+j+len(model)
+				if (start == 0 || !isWordChar(text[start-1])) && (end == len(text) || !isWordChar(text[end])) {
+					return model, true
+				}
+				i = start + 1
+			}
+		}
+	}
+	return "", false
+}
+
+// Fingerprints the cable modem using the unauthenticated HNAP1 device
+// settings action.
+func probeHNAP(ctx context.Context, input *RetrieverInput, client *http.Client, models []string) (string, error) {
+	cfg := newDriverConfig(input)
+	cfg.HTTPClient = client
+	s := newHNAPSession(cfg)
+	resp, err := s.sendReq(ctx, deviceSettingsAction, actionRequest{}, resetToken())
+	if err != nil {
+		return "", err
+	}
+
+	body := actionResponseBody(resp)
+	modelName := subValue(body, "ModelName")
+	model, ok := matchModel(models, modelName, subValue(body, "ModelDescription"))
+	if !ok {
+		return "", fmt.Errorf("%w: model %q (vendor %q) is not supported", ErrUnsupported, modelName, subValue(body, "VendorName"))
+	}
+	return model, nil
+}
+
+// Returns the string representation of the value for the specified key, or
+// an empty string if unavailable.
+func subValue(body actionResponseBody, key string) string {
+	val, ok := body[key]
+	if !ok {
+		return ""
+	}
+	return fmt.Sprintf("%v", val)
+}
+
+// Fingerprints the cable modem using the title, the model markers and the
+// authentication realm of the specified page.
+func probePage(ctx context.Context, client *http.Client, pageURL string, models []string) (string, error) {
+	req, err := http.NewRequestWithContext(ctx, "GET", pageURL, nil)
+	if err != nil {
+		return "", fmt.Errorf("unable to create GET request, reason: %w", err)
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return "", err
+	}
+	defer resp.Body.Close()
+	data, err := io.ReadAll(io.LimitReader(resp.Body, maxProbePageSize))
+	if err != nil {
+		return "", fmt.Errorf("unable to read the response body, reason: %w", err)
+	}
+	page := string(data)
+
+	// The realm is available even if the page requires authentication.
+	texts := []string{resp.Header.Get("WWW-Authenticate")}
+	if resp.StatusCode == http.StatusOK {
+		texts = append(texts, htmlElementText(page, "<title"))
+		for _, marker := range probePageMarkers {
+			texts = append(texts, htmlElementText(page, marker))
+		}
+	}
+	texts = append(texts, resp.Header.Get("Server"))
+
+	if model, ok := matchModel(models, texts...); ok {
+		return model, nil
+	}
+	if resp.StatusCode != http.StatusOK {
+		return "", fmt.Errorf("no supported model found, status code: %d", resp.StatusCode)
+	}
+	return "", fmt.Errorf("no supported model found in the title %q", htmlElementText(page, "<title"))
+}
+
+// Returns the normalized text of the element whose opening tag contains the
+// specified marker (case insensitive), up to the next tag.
+func htmlElementText(page string, marker string) string {
+	lower := strings.ToLower(page)
+	i := strings.Index(lower, strings.ToLower(marker))
+	if i < 0 {
+		return ""
+	}
+	start := strings.IndexByte(page[i:], '>')
+	if start < 0 {
+		return ""
+	}
+	text := page[i+start+1:]
+	if end := strings.IndexByte(text, '<'); end >= 0 {
+		text = text[:end]
+	}
+	return normalizeHTMLText(text)
+}
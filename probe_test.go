@@ -0,0 +1,144 @@
+package cablemodemutil
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+
+	"github.com/tuxdude/cablemodemutil/cablemodemsim"
+)
+
+func TestMatchModel(t *testing.T) {
+	models := []string{"MB8611", "SB8200", "CM600", "S33"}
+	tests := []struct {
+		text  string
+		want  string
+		found bool
+	}{
+		{"ARRIS SURFboard S33 DOCSIS 3.1", "S33", true},
+		{"NETGEAR Gateway cm600", "CM600", true},
+		{`Basic realm="NETGEAR CM600"`, "CM600", true},
+		{"Motorola MB8611", "MB8611", true},
+		{"MS33", "", false},
+		{"SB82001", "", false},
+		{"", "", false},
+	}
+
+	for _, test := range tests {
+		got, found := matchModel(models, test.text)
+		if got != test.want || found != test.found {
+			t.Errorf("%q: matchModel() = (%q, %t) want: (%q, %t)", test.text, got, found, test.want, test.found)
+		}
+	}
+}
+
+func TestProbeHNAP(t *testing.T) {
+	sim := cablemodemsim.New(&cablemodemsim.Config{
+		Username: testUsername,
+		Password: testPassword,
+	})
+	srv := httptest.NewTLSServer(sim)
+	defer srv.Close()
+
+	res, err := Probe(context.Background(), &RetrieverInput{
+		Host:          strings.TrimPrefix(srv.URL, "https://"),
+		HTTPClient:    srv.Client(),
+		Username:      testUsername,
+		ClearPassword: testPassword,
+	})
+	if err != nil {
+		t.Fatalf("Probe() failed, reason: %s", err)
+	}
+	if res.Vendor != "Arris" || res.Model != "S33" || res.Protocol != "https" {
+		t.Errorf("Probe() = {Vendor:%q Model:%q Protocol:%q} want: {Vendor:\"Arris\" Model:\"S33\" Protocol:\"https\"}",
+			res.Vendor, res.Model, res.Protocol)
+	}
+	if _, err = res.Retriever.Status(); err != nil {
+		t.Errorf("Status() using the probed retriever failed, reason: %s", err)
+	}
+}
+
+func TestProbeHTML(t *testing.T) {
+	tests := []struct {
+		auth   htmlAuthScheme
+		pages  map[string]string
+		model  string
+		vendor string
+	}{
+		{
+			htmlAuthNone,
+			map[string]string{arrisConnectionStatusPage: "sb8200_cmconnectionstatus.html"},
+			"SB8200",
+			"Arris",
+		},
+		{
+			htmlAuthBasic,
+			map[string]string{netgearDocsisStatusPage: "cm600_docsisstatus.htm"},
+			"CM600",
+			"Netgear",
+		},
+	}
+
+	for _, test := range tests {
+		srv := newHTMLTestServer(t, test.auth, test.pages)
+		// The protocol is unspecified, and hence the probe falls back to
+		// http after https fails.
+		res, err := Probe(context.Background(), &RetrieverInput{
+			Host:          strings.TrimPrefix(srv.URL, "http://"),
+			HTTPClient:    srv.Client(),
+			Username:      testUsername,
+			ClearPassword: testPassword,
+		})
+		srv.Close()
+		if err != nil {
+			t.Errorf("%q: Probe() failed, reason: %s", test.model, err)
+			continue
+		}
+		if res.Model != test.model || res.Vendor != test.vendor || res.Protocol != "http" {
+			t.Errorf("%q: Probe() = {Vendor:%q Model:%q Protocol:%q} want: {Vendor:%q Model:%q Protocol:\"http\"}",
+				test.model, res.Vendor, res.Model, res.Protocol, test.vendor, test.model)
+		}
+	}
+}
+
+func TestProbeUnknown(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		_, _ = w.Write([]byte("<html><head><title>Some Router</title></head></html>"))
+	}))
+	defer srv.Close()
+
+	_, err := Probe(context.Background(), &RetrieverInput{
+		Host:       strings.TrimPrefix(srv.URL, "http://"),
+		Protocol:   "http",
+		HTTPClient: srv.Client(),
+	})
+	if !errors.Is(err, ErrUnsupported) {
+		t.Fatalf("Probe() error = %v want: ErrUnsupported", err)
+	}
+	var pErr *ProbeError
+	if !errors.As(err, &pErr) || len(pErr.Attempts) != 1+len(probePages) {
+		t.Fatalf("Probe() error = %v want: ProbeError with %d attempts", err, 1+len(probePages))
+	}
+	if !strings.Contains(err.Error(), `"Some Router"`) {
+		t.Errorf("Probe() error = %q want the title of the page in the error", err)
+	}
+}
+
+func TestProbeUnreachable(t *testing.T) {
+	srv := httptest.NewServer(http.NotFoundHandler())
+	host := strings.TrimPrefix(srv.URL, "http://")
+	srv.Close()
+
+	_, err := Probe(context.Background(), &RetrieverInput{Host: host})
+	if errors.Is(err, ErrUnsupported) {
+		t.Errorf("Probe() error = %v want an error not matching ErrUnsupported", err)
+	}
+	var urlErr *url.Error
+	if !errors.As(err, &urlErr) {
+		t.Errorf("Probe() error = %v want the network error wrapped", err)
+	}
+}
@@ -0,0 +1,304 @@
+package cablemodemutil
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"regexp"
+	"strings"
+	"sync"
+)
+
+const redactedValue = "REDACTED"
+
+// Keys in the request and response payloads whose values are redacted while
+// recording, since they contain credentials or identify the cable modem.
+// nolint:gochecknoglobals
+var defaultRedactedKeys = []string{
+	// Credentials and session information.
+	"Username",
+	"LoginPassword",
+	"Cookie",
+	"PublicKey",
+	"Challenge",
+	"CurrentLogin",
+	"CurrentNameAdmin",
+	"CurrentNameUser",
+	"CurrentPwAdmin",
+	"CurrentPwUser",
+	// Device identifiers.
+	"SerialNumber",
+	"StatusSoftwareSerialNum",
+	"MacAddress",
+	"StatusSoftwareMac",
+}
+
+// Matches the cable modem MAC address attribute in log entries.
+// nolint:gochecknoglobals
+var cmMACAttributeRegexp = regexp.MustCompile(`CM-MAC=[^;]*`)
+
+// Matches the MAC addresses in the responses which are not valid JSON
+// (Eg. the HTML status pages).
+// nolint:gochecknoglobals
+var macAddressRegexp = regexp.MustCompile(`\b[0-9A-Fa-f]{2}(?:[:-][0-9A-Fa-f]{2}){5}\b`)
+
+// Matches the serial number following its label in the responses which are
+// not valid JSON, along with the markup in between (Eg. the table cells in
+// the HTML status pages).
+// nolint:gochecknoglobals
+var serialNumberRegexp = regexp.MustCompile(`(?i)(serial number\s*:?\s*(?:<[^>]*>\s*)*)[^<\s][^<]*`)
+
+// Fixture contains the HNAP exchanges recorded with a cable modem, which can
+// be replayed later.
+type Fixture struct {
+	// The model of the cable modem, used for selecting the driver while
+	// replaying. Defaults to DefaultModel if unspecified.
+	Model string `json:"model,omitempty"`
+	// The recorded exchanges in the order they occurred.
+	Exchanges []Exchange `json:"exchanges"`
+}
+
+// Exchange contains a recorded request and response pair for a SOAP action.
+type Exchange struct {
+	// The SOAP action.
+	Action string `json:"action"`
+	// The request payload.
+	Request json.RawMessage `json:"request,omitempty"`
+	// HTTP status code in the response.
+	StatusCode int `json:"statusCode"`
+	// The response payload if it is valid JSON.
+	Response json.RawMessage `json:"response,omitempty"`
+	// The response payload if it is not valid JSON, with the serial numbers
+	// and MAC addresses redacted. The response to the login request of the
+	// cable modems serving HTML pages is redacted entirely since it contains
+	// the credential.
+	ResponseText string `json:"responseText,omitempty"`
+}
+
+// LoadFixture loads the fixture from the specified file.
+func LoadFixture(path string) (*Fixture, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("unable to read fixture %q, reason: %w", path, err)
+	}
+	var f Fixture
+	err = json.Unmarshal(data, &f)
+	if err != nil {
+		return nil, fmt.Errorf("unable to decode fixture %q, reason: %w", path, err)
+	}
+	return &f, nil
+}
+
+// Save saves the fixture to the specified file.
+func (f *Fixture) Save(path string) error {
+	data, err := json.MarshalIndent(f, "", "  ")
+	if err != nil {
+		return fmt.Errorf("unable to encode fixture, reason: %w", err)
+	}
+	err = os.WriteFile(path, append(data, '\n'), 0o600)
+	if err != nil {
+		return fmt.Errorf("unable to write fixture %q, reason: %w", path, err)
+	}
+	return nil
+}
+
+// RecordingTransport is an http.RoundTripper which records the HNAP
+// exchanges with the cable modem into a fixture, redacting the credentials,
+// serial numbers and MAC addresses. Use it as the Transport in the
+// RetrieverInput.
+type RecordingTransport struct {
+	next       http.RoundTripper
+	redactKeys map[string]bool
+	fixture    Fixture
+	mu         sync.Mutex
+}
+
+// NewRecordingTransport returns a new recording transport which sends the
+// requests using the specified transport (http.DefaultTransport if nil).
+// Values for the specified keys are redacted in addition to the defaults.
+func NewRecordingTransport(next http.RoundTripper, extraRedactKeys ...string) *RecordingTransport {
+	t := RecordingTransport{}
+	t.next = next
+	if t.next == nil {
+		t.next = http.DefaultTransport
+	}
+	t.redactKeys = make(map[string]bool)
+	for _, k := range defaultRedactedKeys {
+		t.redactKeys[k] = true
+	}
+	for _, k := range extraRedactKeys {
+		t.redactKeys[k] = true
+	}
+	return &t
+}
+
+// RoundTrip sends the request and records the exchange.
+func (t *RecordingTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	var reqBody []byte
+	if req.Body != nil {
+		var err error
+		reqBody, err = io.ReadAll(req.Body)
+		req.Body.Close()
+		if err != nil {
+			return nil, fmt.Errorf("recorder unable to read the request body, reason: %w", err)
+		}
+		req.Body = io.NopCloser(bytes.NewReader(reqBody))
+	}
+
+	resp, err := t.next.RoundTrip(req)
+	if err != nil {
+		return nil, err
+	}
+	respBody, err := io.ReadAll(resp.Body)
+	resp.Body.Close()
+	if err != nil {
+		return nil, fmt.Errorf("recorder unable to read the response body, reason: %w", err)
+	}
+	resp.Body = io.NopCloser(bytes.NewReader(respBody))
+
+	ex := Exchange{
+		Action:     actionFromHeader(req.Header.Get(actionHeader)),
+		Request:    t.redactJSON(reqBody),
+		StatusCode: resp.StatusCode,
+		Response:   t.redactJSON(respBody),
+	}
+	if ex.Response == nil {
+		ex.ResponseText = redactText(req, respBody)
+	}
+	t.mu.Lock()
+	t.fixture.Exchanges = append(t.fixture.Exchanges, ex)
+	t.mu.Unlock()
+	return resp, nil
+}
+
+// Fixture returns a copy of the fixture with the exchanges recorded so far.
+func (t *RecordingTransport) Fixture() *Fixture {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	f := Fixture{
+		Exchanges: make([]Exchange, len(t.fixture.Exchanges)),
+	}
+	copy(f.Exchanges, t.fixture.Exchanges)
+	return &f
+}
+
+// Returns the redacted copy of the specified JSON payload, nil if the
+// payload is not valid JSON.
+func (t *RecordingTransport) redactJSON(data []byte) json.RawMessage {
+	var payload interface{}
+	if err := json.Unmarshal(data, &payload); err != nil {
+		return nil
+	}
+	res, err := json.Marshal(t.redact(payload))
+	if err != nil {
+		return nil
+	}
+	return res
+}
+
+// Returns the redacted copy of the specified response payload which is not
+// valid JSON.
+func redactText(req *http.Request, data []byte) string {
+	if len(data) == 0 {
+		return ""
+	}
+	if strings.HasPrefix(req.URL.RawQuery, "login_") {
+		// The response is the credential for the cookie based login.
+		return redactedValue
+	}
+	text := macAddressRegexp.ReplaceAllString(string(data), redactedValue)
+	return serialNumberRegexp.ReplaceAllString(text, "${1}"+redactedValue)
+}
+
+// Redacts the values of the sensitive keys within the specified decoded JSON
+// value recursively.
+func (t *RecordingTransport) redact(val interface{}) interface{} {
+	switch v := val.(type) {
+	case map[string]interface{}:
+		for key, elem := range v {
+			if s, ok := elem.(string); ok && t.redactKeys[key] && s != "" {
+				v[key] = redactedValue
+				continue
+			}
+			v[key] = t.redact(elem)
+		}
+		return v
+	case []interface{}:
+		for i, elem := range v {
+			v[i] = t.redact(elem)
+		}
+		return v
+	case string:
+		return cmMACAttributeRegexp.ReplaceAllString(v, "CM-MAC="+redactedValue)
+	}
+	return val
+}
+
+// ReplayTransport is an http.RoundTripper which serves the HNAP exchanges
+// recorded in a fixture without connecting to a cable modem. Use it as the
+// Transport in the RetrieverInput. The requests for each action are served
+// the recorded responses for that action in order, and the last recorded
+// response is repeated once the responses are exhausted. The HNAP auth and
+// the cookies in the requests are not validated.
+type ReplayTransport struct {
+	exchanges map[string][]Exchange
+	next      map[string]int
+	mu        sync.Mutex
+}
+
+// NewReplayTransport returns a new replay transport serving the exchanges in
+// the specified fixture.
+func NewReplayTransport(f *Fixture) *ReplayTransport {
+	t := ReplayTransport{}
+	t.exchanges = make(map[string][]Exchange)
+	for _, ex := range f.Exchanges {
+		t.exchanges[ex.Action] = append(t.exchanges[ex.Action], ex)
+	}
+	t.next = make(map[string]int)
+	return &t
+}
+
+// RoundTrip serves the recorded response for the action in the request.
+func (t *ReplayTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	if req.Body != nil {
+		req.Body.Close()
+	}
+	action := actionFromHeader(req.Header.Get(actionHeader))
+
+	t.mu.Lock()
+	exchanges := t.exchanges[action]
+	if len(exchanges) == 0 {
+		t.mu.Unlock()
+		return nil, fmt.Errorf("no recorded exchange for SOAP action %q", action)
+	}
+	i := t.next[action]
+	if i < len(exchanges)-1 {
+		t.next[action] = i + 1
+	}
+	ex := exchanges[i]
+	t.mu.Unlock()
+
+	body := []byte(ex.Response)
+	if ex.Response == nil {
+		body = []byte(ex.ResponseText)
+	}
+	return &http.Response{
+		Status:        fmt.Sprintf("%d %s", ex.StatusCode, http.StatusText(ex.StatusCode)),
+		StatusCode:    ex.StatusCode,
+		Proto:         "HTTP/1.1",
+		ProtoMajor:    1,
+		ProtoMinor:    1,
+		Header:        http.Header{contentTypeHeader: []string{contentTypeHeaderValue}},
+		Body:          io.NopCloser(bytes.NewReader(body)),
+		ContentLength: int64(len(body)),
+		Request:       req,
+	}, nil
+}
+
+// Returns the SOAP action from the specified SOAP action header value.
+func actionFromHeader(header string) string {
+	return strings.TrimPrefix(strings.Trim(header, "\""), soapNamespace+"/")
+}
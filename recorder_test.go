@@ -0,0 +1,104 @@
+package cablemodemutil
+
+import (
+	"encoding/json"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// Returns a retriever which replays the exchanges in the specified fixture.
+func newReplayRetriever(f *Fixture) *Retriever {
+	return NewStatusRetriever(&RetrieverInput{
+		Host:          "192.168.100.1",
+		Protocol:      "https",
+		Model:         f.Model,
+		Transport:     NewReplayTransport(f),
+		Username:      testUsername,
+		ClearPassword: testPassword,
+	})
+}
+
+func TestRecordingTransportRedacts(t *testing.T) {
+	r, _ := newTestRetriever(t, testPassword)
+	client := r.driver.(*arrisDriver).session.client.client
+	rec := NewRecordingTransport(client.Transport)
+	client.Transport = rec
+
+	_, err := r.Status()
+	if err != nil {
+		t.Fatalf("Status() failed, reason: %s", err)
+	}
+
+	data, err := json.Marshal(rec.Fixture())
+	if err != nil {
+		t.Fatalf("unable to encode the fixture, reason: %s", err)
+	}
+	secrets := []string{`"Username":"` + testUsername + `"`, "1234567890ABCDEF", "AA:BB:CC:DD:EE:FF", "aa:bb:cc:dd:ee:ff"}
+	for _, secret := range secrets {
+		if strings.Contains(string(data), secret) {
+			t.Errorf("recorded fixture contains %q which must have been redacted", secret)
+		}
+	}
+
+	// The recorded fixture must be usable for replaying.
+	_, err = newReplayRetriever(rec.Fixture()).Status()
+	if err != nil {
+		t.Errorf("Status() using the replayed fixture failed, reason: %s", err)
+	}
+}
+
+func TestRecordingTransportRedactsHTML(t *testing.T) {
+	srv := newHTMLTestServer(t, htmlAuthCookie, map[string]string{
+		arrisConnectionStatusPage: "sb8200_cmconnectionstatus.html",
+		arrisSoftwareInfoPage:     "sb8200_cmswinfo.html",
+		arrisEventLogPage:         "sb8200_cmeventlog.html",
+	})
+	defer srv.Close()
+
+	rec := NewRecordingTransport(srv.Client().Transport)
+	r, err := NewRetriever(&RetrieverInput{
+		Host:          strings.TrimPrefix(srv.URL, "http://"),
+		Model:         "SB8200",
+		Transport:     rec,
+		Username:      testUsername,
+		ClearPassword: testPassword,
+	})
+	if err != nil {
+		t.Fatalf("NewRetriever() failed, reason: %s", err)
+	}
+	if _, err = r.Status(); err != nil {
+		t.Fatalf("Status() failed, reason: %s", err)
+	}
+
+	data, err := json.Marshal(rec.Fixture())
+	if err != nil {
+		t.Fatalf("unable to encode the fixture, reason: %s", err)
+	}
+	for _, secret := range []string{testCredential, "1234567890ABCDEF", "AA:BB:CC:DD:EE:FF", "00:11:22:33:44:66"} {
+		if strings.Contains(string(data), secret) {
+			t.Errorf("recorded fixture contains %q which must have been redacted", secret)
+		}
+	}
+}
+
+// The golden fixtures bundled in the repository are synthetic (recorded from
+// cablemodemsim or built by hand), and only guard the drivers against
+// regressions in the parsing of the known responses. Redacted recordings from
+// real cable modems placed alongside are replayed the same way.
+func TestReplayGoldenFixtures(t *testing.T) {
+	paths, err := filepath.Glob("testdata/golden/*.json")
+	if err != nil {
+		t.Fatalf("unable to list the golden fixtures, reason: %s", err)
+	}
+	for _, path := range paths {
+		f, err := LoadFixture(path)
+		if err != nil {
+			t.Errorf("%q: LoadFixture() failed, reason: %s", path, err)
+			continue
+		}
+		if _, err = newReplayRetriever(f).Status(); err != nil {
+			t.Errorf("%q: Status() failed, reason: %s", path, err)
+		}
+	}
+}
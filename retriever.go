@@ -1,70 +1,54 @@
 package cablemodemutil
 
 import (
-	"bytes"
-	"encoding/json"
-	"fmt"
-	"sync"
+	"context"
+	"crypto/tls"
+	"net/http"
 	"time"
 )
 
-const (
-	urlFormat           = "%s://%s/HNAP1/"
-	tokenExpiryDuration = 10 * time.Minute
-	loginAction         = "Login"
-	queryAction         = "GetMultipleHNAPs"
-)
-
-// nolint:gochecknoglobals
-var statusSubCommands = []string{
-	// MAC address, serial number and model info.
-	"GetArrisRegisterInfo",
-	// Software version info along with device MAC addr / serial.
-	"GetCustomerStatusSoftware",
-	// Short Device Status and signal info.
-	"GetArrisDeviceStatus",
-	// Current Time, uptime and short connection status.
-	"GetCustomerStatusConnectionInfo",
-	// Detailed connection status.
-	"GetCustomerStatusStartupSequence",
-	// Downstream channel info.
-	"GetCustomerStatusDownstreamChannelInfo",
-	// Upstream channel info.
-	"GetCustomerStatusUpstreamChannelInfo",
-	// Downstream/Upstream Frequency summary and configurable settings.
-	"GetArrisConfigurationInfo",
-	// Event log.
-	"GetCustomerStatusLog",
-	// User login/password information (Not so useful).
-	"GetCustomerStatusSecAccount",
-	// Ask me later and never ask (Not so useful).
-	"GetArrisRegisterStatus",
-}
+const connectionTimeout = 15 * time.Second
 
 // Retriever is used to retrieve the current status of the Cable Modem.
 type Retriever struct {
-	client        *httpClient
-	username      string
-	clearPassword string
-	debug         RetrieverDebug
-	tok           *token
-	tokMu         sync.Mutex
+	driver Driver
 }
 
 // RetrieverInput is used to specify the input for building a Retriever.
 type RetrieverInput struct {
 	// The host name or IP address of the cable modem device.
 	Host string
+	// Model of the cable modem, used for selecting the registered driver.
+	// Defaults to DefaultModel if unspecified.
+	Model string
 	// The protocol used to connect to the cable modem, either
 	// "http" or "https".
 	Protocol string
 	// If true skips verifying the cable modem's SSL certificate, false
-	// otherwise.
+	// otherwise. Ignored if either HTTPClient or Transport is specified.
 	SkipVerifyCert bool
+	// Optional HTTP client used for all the requests sent to the cable
+	// modem. Takes precedence over Transport when both are specified.
+	HTTPClient *http.Client
+	// Optional HTTP transport used for all the requests sent to the cable
+	// modem, useful for configuring proxies, custom root CAs, client
+	// certificates, custom dialers or instrumentation.
+	Transport http.RoundTripper
+	// Optional timeout for each HTTP request sent to the cable modem. If
+	// unspecified, defaults to 15 seconds unless HTTPClient is specified,
+	// in which case the client's own timeout is used.
+	Timeout time.Duration
 	// User name for authenticating with the cable modem.
 	Username string
 	// Password for authenticating with the cable modem.
 	ClearPassword string
+	// Optional store for persisting the authenticated session across
+	// process restarts, consulted before logging in to the cable modem.
+	TokenStore TokenStore
+	// Hash algorithm used for authenticating with the HNAP1 cable modems.
+	// Defaults to HashAlgorithmAuto, negotiating the algorithm while
+	// logging in.
+	HashAlgorithm HashAlgorithm
 	// Debugging options.
 	Debug RetrieverDebug
 }
@@ -83,309 +67,142 @@ type RetrieverDebug struct {
 	DebugResp bool
 }
 
-// The token object containing the state of the authenticated session with
-// the cable modem.
-type token struct {
-	// The UID of the session provided by the cable modem during
-	// authentication.
-	uid string
-	// The private key of the session after authentication, generated based
-	// on public key, challenge from the cable modem and the supplied password.
-	privateKey string
-	// The expiry timestamp of the credentials stored in this session.
-	expiry time.Time
-}
-
-// Initial response from the cable modem to allow the client to initiate
-// authentication.
-type loginResponse struct {
-	// The UID of the session provided by the cable modem during
-	// authentication.
-	uid string
-	// The public key provided by the cable modem during authentication.
-	publicKey string
-	// The challenge message provided by the cable modem during authentication.
-	challenge string
-}
-
-// actionRequest represents the payload of the request containing the SOAP
-// action command.
-type actionRequest map[string]string
-
-// actionResponse represents the payload of the response to a SOAP action
-// command request.
-type actionResponse map[string]interface{}
-
-// soapRequest represents the full SOAP request body.
-type soapRequest map[string]actionRequest
-
-// soapResponse represents the full SOAP response body to a SOAP request sent.
-type soapResponse map[string]actionResponse
-
-// Returns a token that has been reset to the initial state.
-func resetToken() *token {
-	return &token{
-		privateKey: "withoutLoginKey",
-	}
-}
-
-// Encodes the specified payload for the specified action into a byte buffer
-// to send this as a request.
-func encodePayload(action string, payload actionRequest) (*bytes.Buffer, error) {
-	req := soapRequest{
-		action: payload,
-	}
-	buf := new(bytes.Buffer)
-	err := json.NewEncoder(buf).Encode(req)
-	if err != nil {
-		return nil, fmt.Errorf("failed to encode request as JSON, reason: %w", err)
-	}
-	return buf, nil
-}
-
-// Decodes the specified byte array response for the specified action into the
-// response payload.
-func decodePayload(action string, resp *[]byte) (actionResponse, error) {
-	var payload soapResponse
-	err := json.Unmarshal(*resp, &payload)
-	if err != nil {
-		return nil, fmt.Errorf("failed to decode response payload, reason:%w", err)
-	}
-	return unpackResponse(action, payload)
-}
-
-// Validates and unpacks the response for the specified SOAP action.
-func unpackResponse(action string, resp soapResponse) (actionResponse, error) {
-	if len(resp) != 1 {
-		return nil, fmt.Errorf(
-			"action: %s, invalid number of keys (%d) in response, expected 1.\nresponse: %v",
-			action,
-			len(resp),
-			prettyPrintJSON(resp),
-		)
-	}
-
-	respKey := actionResponseKey(action)
-	unpacked, keyExists := resp[respKey]
-	if !keyExists {
-		return nil, fmt.Errorf(
-			"action: %s, unable to find the response key %q in response.\nresponse: %v",
-			action,
-			respKey,
-			prettyPrintJSON(resp),
-		)
-	}
-
-	resultKey := actionResultKey(action)
-	result, keyExists := unpacked[resultKey]
-	if !keyExists {
-		return nil, fmt.Errorf(
-			"action: %s, unable to find the result key %q in unpacked response.\nunpacked response: %v",
-			action,
-			resultKey,
-			prettyPrintJSON(unpacked),
-		)
-	}
-	if result != "OK" {
-		return nil, fmt.Errorf(
-			"action: %s, result in unpacked resposne is %q, expected \"OK\".\nunpacked response: %v",
-			action,
-			result,
-			prettyPrintJSON(unpacked),
-		)
-	}
-
-	return unpacked, nil
-}
-
 // NewStatusRetriever returns a new retriever object that can be used to
-// query the Cable Modem status.
+// query the Cable Modem status. If no driver is registered for the model in
+// the input, all the operations of the retriever fail with ErrUnsupported;
+// use NewRetriever to detect this upfront.
 func NewStatusRetriever(input *RetrieverInput) *Retriever {
-	url := fmt.Sprintf(urlFormat, input.Protocol, input.Host)
-	r := Retriever{}
-	r.client = newHTTPClient(url, input.SkipVerifyCert, &input.Debug)
-	r.username = input.Username
-	r.clearPassword = input.ClearPassword
-	r.debug = input.Debug
-	r.tok = resetToken()
-	return &r
-}
-
-// Persist the token in the object.
-func (r *Retriever) persistToken(tok *token) {
-	r.tokMu.Lock()
-	r.tok = &token{
-		privateKey: tok.privateKey,
-		uid:        tok.uid,
-		expiry:     tok.expiry,
-	}
-	r.tokMu.Unlock()
-	if r.debug.Debug {
-		fmt.Println("Persisting a new token.")
-		debugToken(tok)
-	}
-}
-
-// Retrieves a copy of the persisted token.
-func (r *Retriever) getToken() *token {
-	r.tokMu.Lock()
-	res := &token{
-		privateKey: r.tok.privateKey,
-		uid:        r.tok.uid,
-		expiry:     r.tok.expiry,
+	r, err := NewRetriever(input)
+	if err != nil {
+		return NewRetrieverWithDriver(&errDriver{err: err})
 	}
-	r.tokMu.Unlock()
-	return res
+	return r
 }
 
-// Sends the SOAP request for the specified action containing the specified
-// payload.
-func (r *Retriever) sendReq(action string, payload actionRequest, tok *token) (actionResponse, error) {
-	req, err := encodePayload(action, payload)
-	if err != nil {
-		return nil, err
+// NewRetriever returns a new retriever object that can be used to query the
+// Cable Modem status, using the driver registered for the model in the
+// input.
+func NewRetriever(input *RetrieverInput) (*Retriever, error) {
+	model := input.Model
+	if model == "" {
+		model = DefaultModel
 	}
-	resp, err := r.client.sendPOST(action, req, tok)
+	factory, err := lookupDriver(model)
 	if err != nil {
 		return nil, err
 	}
-	return decodePayload(action, resp)
+	return NewRetrieverWithDriver(factory(newDriverConfig(input))), nil
 }
 
-// Retrieves the cookie, public key and challenge information from the cable
-// modem that can be used for initiating an authentication request.
-func (r *Retriever) getLoginResponse() (*loginResponse, error) {
-	payload := actionRequest{
-		"LoginPassword": "",
-		"Captcha":       "",
-		"PrivateLogin":  "LoginPassword",
-		"Action":        "request",
-		"Username":      r.username,
-	}
-	tok := resetToken()
-	resp, err := r.sendReq(loginAction, payload, tok)
-	if err != nil {
-		return nil, fmt.Errorf("failed to retrieve login challenge\nreason: %w", err)
-	}
+// NewRetrieverWithDriver returns a new retriever object that uses the
+// specified driver.
+func NewRetrieverWithDriver(d Driver) *Retriever {
+	return &Retriever{driver: d}
+}
 
-	respBody := actionResponseBody(resp)
-	result := loginResponse{}
-	result.uid, err = parseString(respBody, "Cookie", "Cookie in login response")
-	if err != nil {
-		return nil, err
-	}
-	result.publicKey, err = parseString(respBody, "PublicKey", "Public key in login response")
-	if err != nil {
-		return nil, err
-	}
-	result.challenge, err = parseString(respBody, "Challenge", "Challenge in login response")
-	if err != nil {
-		return nil, err
+// Returns the driver configuration derived from the specified input.
+func newDriverConfig(input *RetrieverInput) *DriverConfig {
+	return &DriverConfig{
+		Host:          input.Host,
+		Protocol:      input.Protocol,
+		HTTPClient:    newStdHTTPClient(input),
+		Username:      input.Username,
+		ClearPassword: input.ClearPassword,
+		TokenStore:    input.TokenStore,
+		HashAlgorithm: input.HashAlgorithm,
+		Debug:         input.Debug,
 	}
-
-	return &result, nil
 }
 
-// Performs authentication with the cable modem and returns the response.
-func (r *Retriever) doAuth(challenge string, tok *token) error {
-	hashedPassword, err := genHashedPassword(tok.privateKey, challenge)
-	if err != nil {
-		return fmt.Errorf("auth failed while generating hashed password, reason: %w", err)
+// Returns the HTTP client built according to the HTTP options in the
+// specified input.
+func newStdHTTPClient(input *RetrieverInput) *http.Client {
+	timeout := connectionTimeout
+	if input.Timeout > 0 {
+		timeout = input.Timeout
 	}
 
-	payload := actionRequest{
-		"LoginPassword": hashedPassword,
-		"Captcha":       "",
-		"PrivateLogin":  "LoginPassword",
-		"Action":        "login",
-		"Username":      r.username,
+	switch {
+	case input.HTTPClient != nil:
+		// Use a shallow copy to avoid modifying the caller's client when
+		// overriding the timeout.
+		client := *input.HTTPClient
+		if input.Timeout > 0 {
+			client.Timeout = input.Timeout
+		}
+		return &client
+	case input.Transport != nil:
+		return &http.Client{
+			Timeout:   timeout,
+			Transport: input.Transport,
+		}
 	}
-	_, err = r.sendReq(loginAction, payload, tok)
-	if err != nil {
-		return fmt.Errorf("auth failed.\nreason: %w", err)
+	return &http.Client{
+		Timeout: timeout,
+		Transport: &http.Transport{
+			TLSClientConfig: &tls.Config{InsecureSkipVerify: input.SkipVerifyCert}, // nolint:gosec
+		},
 	}
-	return nil
 }
 
-// Login to the cable modem using the specified username and password.
-func (r *Retriever) login() (*token, error) {
-	loginResp, err := r.getLoginResponse()
-	if err != nil {
-		return nil, err
-	}
-	// Compute the expiry time as soon as we obtain the response.
-	expiry := time.Now().Add(tokenExpiryDuration)
+// Driver returns the driver used by the retriever.
+func (r *Retriever) Driver() Driver {
+	return r.driver
+}
 
-	privateKey, err := genPrivateKey(loginResp.publicKey, loginResp.challenge, r.clearPassword)
-	if err != nil {
-		return nil, fmt.Errorf("login failed while generating private key, reason: %w", err)
-	}
+// Authenticate logs in to the cable modem, discarding the current session if
+// any. Logging in happens automatically while retrieving the status, so
+// this is only required for verifying the credentials upfront.
+func (r *Retriever) Authenticate(ctx context.Context) error {
+	return r.driver.Authenticate(ctx)
+}
 
-	tok := &token{
-		uid:        loginResp.uid,
-		privateKey: privateKey,
-		expiry:     expiry,
-	}
-	err = r.doAuth(loginResp.challenge, tok)
-	if err != nil {
-		return nil, err
-	}
-	return tok, nil
+// Returns the sections specified in the options which are supported by the
+// driver.
+func (r *Retriever) sections(opts *StatusOptions) StatusSection {
+	return opts.sections() & r.driver.Capabilities().Sections
 }
 
 // RawStatus retrieves the current detailed raw status from the cable modem.
 func (r *Retriever) RawStatus() (CableModemRawStatus, error) {
-	var err error
-	loginAttempted := false
-	payload := make(actionRequest)
-	for _, cmd := range statusSubCommands {
-		payload[cmd] = ""
-	}
-
-	tok := r.getToken()
-	for {
-		// If the token has expired, login to generate a fresh token.
-		if time.Now().After(tok.expiry) {
-			if r.debug.Debug {
-				fmt.Println("Token expired, will attempt a new login.")
-				debugToken(tok)
-			}
-			loginAttempted = true
-			tok, err = r.login()
-			if err != nil {
-				return nil, err
-			}
-			r.persistToken(tok)
-		}
+	return r.RawStatusContext(context.Background())
+}
 
-		var status actionResponse
-		// Compute the new token expiry time based on when we send the request.
-		newExpiry := time.Now().Add(tokenExpiryDuration)
-		// Fetch the current status.
-		status, err = r.sendReq(queryAction, payload, tok)
-		if err == nil {
-			tok.expiry = newExpiry
-			r.persistToken(tok)
-			return CableModemRawStatus(status), nil
-		}
+// RawStatusContext retrieves the current detailed raw status from the cable
+// modem. The specified context is used for the login handshake (if
+// required) and the status query, and cancellation or expiry of the context
+// aborts the retrieval.
+func (r *Retriever) RawStatusContext(ctx context.Context) (CableModemRawStatus, error) {
+	return r.RawStatusWithOptions(ctx, nil)
+}
 
-		// If there is a failure in fetching the current status, and we
-		// didn't generate a fresh token just now, generate a new token
-		// and re-attempt fetching the current status.
-		if loginAttempted {
-			break
-		}
-		tok = resetToken()
-	}
-	return nil, err
+// RawStatusWithOptions retrieves the current raw status from the cable modem
+// using the specified context, limited to the sections specified in the
+// options.
+func (r *Retriever) RawStatusWithOptions(ctx context.Context, opts *StatusOptions) (CableModemRawStatus, error) {
+	return r.driver.FetchRaw(ctx, r.sections(opts))
 }
 
 // Status retrieves and parses the current detailed status from the cable
 // modem.
 func (r *Retriever) Status() (*CableModemStatus, error) {
-	raw, err := r.RawStatus()
+	return r.StatusContext(context.Background())
+}
+
+// StatusContext retrieves and parses the current detailed status from the
+// cable modem using the specified context.
+func (r *Retriever) StatusContext(ctx context.Context) (*CableModemStatus, error) {
+	return r.StatusWithOptions(ctx, nil)
+}
+
+// StatusWithOptions retrieves and parses the current status from the cable
+// modem using the specified context, limited to the sections specified in
+// the options.
+func (r *Retriever) StatusWithOptions(ctx context.Context, opts *StatusOptions) (*CableModemStatus, error) {
+	sections := r.sections(opts)
+	raw, err := r.driver.FetchRaw(ctx, sections)
 	if err != nil {
 		return nil, err
 	}
-	return ParseRawStatus(raw)
+	return r.driver.Parse(raw, sections)
 }
@@ -0,0 +1,215 @@
+package cablemodemutil
+
+import (
+	"context"
+	"errors"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/tuxdude/cablemodemutil/cablemodemsim"
+)
+
+const (
+	testUsername = "admin"
+	testPassword = "password"
+)
+
+// Starts a simulated cable modem and returns a retriever connected to it
+// using the specified password.
+func newTestRetriever(t *testing.T, password string) (*Retriever, *cablemodemsim.Simulator) {
+	t.Helper()
+	sim := cablemodemsim.New(&cablemodemsim.Config{
+		Username: testUsername,
+		Password: testPassword,
+	})
+	srv := httptest.NewTLSServer(sim)
+	t.Cleanup(srv.Close)
+
+	r := NewStatusRetriever(&RetrieverInput{
+		Host:          strings.TrimPrefix(srv.URL, "https://"),
+		Protocol:      "https",
+		HTTPClient:    srv.Client(),
+		Username:      testUsername,
+		ClearPassword: password,
+	})
+	return r, sim
+}
+
+func TestRetrieverStatus(t *testing.T) {
+	r, sim := newTestRetriever(t, testPassword)
+
+	st, err := r.Status()
+	if err != nil {
+		t.Fatalf("Status() failed, reason: %s", err)
+	}
+	if st.Info.Model != "S33" {
+		t.Errorf("Status() Info.Model = %q want: %q", st.Info.Model, "S33")
+	}
+
+	// The session must be reused for subsequent requests.
+	_, err = r.Status()
+	if err != nil {
+		t.Fatalf("Status() failed, reason: %s", err)
+	}
+	if got := sim.Logins(); got != 1 {
+		t.Errorf("Logins() = %d want: 1", got)
+	}
+}
+
+func TestRetrieverWrongPassword(t *testing.T) {
+	r, _ := newTestRetriever(t, "wrong password")
+
+	_, err := r.Status()
+	if !errors.Is(err, ErrAuthFailed) {
+		t.Errorf("Status() error = %v want: %v", err, ErrAuthFailed)
+	}
+	// The hash algorithm is negotiated by default, but the wrong password
+	// must not result in another login attempt using SHA256.
+	if err != nil && strings.Contains(err.Error(), "SHA256") {
+		t.Errorf("Status() error = %q want only the failure using MD5", err)
+	}
+}
+
+func TestRetrieverSessionExpired(t *testing.T) {
+	r, sim := newTestRetriever(t, testPassword)
+
+	_, err := r.Status()
+	if err != nil {
+		t.Fatalf("Status() failed, reason: %s", err)
+	}
+	sim.ExpireSessions()
+
+	// The retriever must transparently login again.
+	_, err = r.Status()
+	if err != nil {
+		t.Fatalf("Status() after session expiry failed, reason: %s", err)
+	}
+	if got := sim.Logins(); got != 2 {
+		t.Errorf("Logins() = %d want: 2", got)
+	}
+}
+
+func TestRetrieverHashAlgorithm(t *testing.T) {
+	tests := []struct {
+		alg      HashAlgorithm
+		password string
+		want     error
+	}{
+		{HashAlgorithmAuto, testPassword, nil},
+		{HashAlgorithmSHA256, testPassword, nil},
+		{HashAlgorithmMD5, testPassword, ErrAuthFailed},
+		// The fallback to SHA256 after the login challenge request is
+		// rejected reports the wrong password.
+		{HashAlgorithmAuto, "wrong password", ErrAuthFailed},
+	}
+
+	for _, test := range tests {
+		sim := cablemodemsim.New(&cablemodemsim.Config{
+			Username:  testUsername,
+			Password:  testPassword,
+			UseSHA256: true,
+		})
+		srv := httptest.NewTLSServer(sim)
+		r := NewStatusRetriever(&RetrieverInput{
+			Host:          strings.TrimPrefix(srv.URL, "https://"),
+			Protocol:      "https",
+			HTTPClient:    srv.Client(),
+			Username:      testUsername,
+			ClearPassword: test.password,
+			HashAlgorithm: test.alg,
+		})
+
+		_, err := r.Status()
+		if err == nil {
+			// The negotiated algorithm must be reused for the session.
+			_, err = r.Status()
+		}
+		srv.Close()
+		if !errors.Is(err, test.want) {
+			t.Errorf("%s: Status() error = %v want: %v", test.alg, err, test.want)
+			continue
+		}
+		if test.want == nil && sim.Logins() != 1 {
+			t.Errorf("%s: Logins() = %d want: 1", test.alg, sim.Logins())
+		}
+	}
+}
+
+var faultTests = []struct {
+	name   string
+	faults cablemodemsim.Faults
+	want   error
+}{
+	{
+		name:   "Malformed JSON",
+		faults: cablemodemsim.Faults{MalformedJSON: true},
+		want:   ErrMalformedResponse,
+	},
+	{
+		name:   "Result not OK",
+		faults: cablemodemsim.Faults{Result: "ERROR"},
+		want:   ErrResultNotOK,
+	},
+}
+
+func TestRetrieverFaults(t *testing.T) {
+	for _, tc := range faultTests {
+		r, sim := newTestRetriever(t, testPassword)
+		sim.SetFaults(tc.faults)
+		if _, err := r.Status(); !errors.Is(err, tc.want) {
+			t.Errorf("%q: Status() error = %v want: %v", tc.name, err, tc.want)
+		}
+		// Only the expired sessions are retried after logging in again.
+		if got := sim.Logins(); got != 1 {
+			t.Errorf("%q: Logins() = %d want: 1", tc.name, got)
+		}
+	}
+}
+
+func TestRetrieverContextDeadline(t *testing.T) {
+	r, sim := newTestRetriever(t, testPassword)
+	sim.SetFaults(cablemodemsim.Faults{Delay: 5 * time.Second})
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	start := time.Now()
+	_, err := r.StatusContext(ctx)
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("StatusContext() error = %v want: %v", err, context.DeadlineExceeded)
+	}
+	if elapsed := time.Since(start); elapsed > 2*time.Second {
+		t.Errorf("StatusContext() took %s, expected to be aborted at the deadline", elapsed)
+	}
+}
+
+func TestRetrieverReboot(t *testing.T) {
+	r, sim := newTestRetriever(t, testPassword)
+
+	err := r.Reboot(context.Background())
+	if err != nil {
+		t.Fatalf("Reboot() failed, reason: %s", err)
+	}
+	calls := sim.Calls()
+	if len(calls) != 1 || calls[0].Action != setConfigurationAction || calls[0].Payload["Action"] != "reboot" {
+		t.Errorf("Reboot() sent %+v want a single %s request with a reboot action", calls, setConfigurationAction)
+	}
+}
+
+func TestRetrieverGetMultiple(t *testing.T) {
+	r, _ := newTestRetriever(t, testPassword)
+
+	resp, err := r.GetMultiple(context.Background(), "GetArrisRegisterInfo", "GetArrisDeviceStatus")
+	if err != nil {
+		t.Fatalf("GetMultiple() failed, reason: %s", err)
+	}
+	if got := resp["GetArrisRegisterInfo"]["ModelName"]; got != "S33" {
+		t.Errorf("GetMultiple() GetArrisRegisterInfo.ModelName = %v want: %q", got, "S33")
+	}
+
+	_, err = r.GetMultiple(context.Background(), "GetUnknownAction")
+	if !errors.Is(err, ErrResultNotOK) {
+		t.Errorf("GetMultiple() error = %v want: %v", err, ErrResultNotOK)
+	}
+}
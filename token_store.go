@@ -0,0 +1,138 @@
+package cablemodemutil
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+)
+
+// Token contains the state of an authenticated session with the cable modem
+// persisted in a TokenStore.
+type Token struct {
+	// The UID of the session provided by the cable modem during
+	// authentication.
+	UID string `json:"uid"`
+	// The private key of the session after authentication.
+	PrivateKey string `json:"privateKey"`
+	// The expiry timestamp of the session.
+	Expiry time.Time `json:"expiry"`
+	// The hash algorithm negotiated for the session.
+	HashAlgorithm HashAlgorithm `json:"hashAlgorithm,omitempty"`
+}
+
+// TokenStore persists the authenticated sessions with the cable modem across
+// process restarts, avoiding the login handshake on every invocation of
+// short-lived processes. The token is saved when a session is established
+// or discarded, and as its expiry gets extended by the requests within the
+// session, at most once every few minutes. Implementations must be safe for
+// concurrent use.
+type TokenStore interface {
+	// Load returns the token for the specified host and user name, nil if
+	// none.
+	Load(host string, username string) (*Token, error)
+	// Save saves the token for the specified host and user name. A nil
+	// token removes the saved token.
+	Save(host string, username string, tok *Token) error
+}
+
+// FileTokenStore is a TokenStore which saves the tokens in a JSON file
+// readable and writable only by the owner.
+type FileTokenStore struct {
+	path string
+	mu   sync.Mutex
+}
+
+// NewFileTokenStore returns a new token store which saves the tokens in the
+// specified file. The file is created when a token is saved for the first
+// time.
+func NewFileTokenStore(path string) *FileTokenStore {
+	return &FileTokenStore{path: path}
+}
+
+// Load returns the token for the specified host and user name, nil if none.
+func (s *FileTokenStore) Load(host string, username string) (*Token, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	tokens, err := s.read()
+	if err != nil {
+		return nil, err
+	}
+	tok, ok := tokens[tokenStoreKey(host, username)]
+	if !ok {
+		return nil, nil
+	}
+	return &tok, nil
+}
+
+// Save saves the token for the specified host and user name. A nil token
+// removes the saved token.
+func (s *FileTokenStore) Save(host string, username string, tok *Token) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	tokens, err := s.read()
+	if err != nil {
+		return err
+	}
+	key := tokenStoreKey(host, username)
+	if tok == nil {
+		if _, ok := tokens[key]; !ok {
+			return nil
+		}
+		delete(tokens, key)
+	} else {
+		tokens[key] = *tok
+	}
+	return s.write(tokens)
+}
+
+// Reads the tokens from the file.
+func (s *FileTokenStore) read() (map[string]Token, error) {
+	tokens := make(map[string]Token)
+	data, err := os.ReadFile(s.path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return tokens, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("unable to read token store %q, reason: %w", s.path, err)
+	}
+	err = json.Unmarshal(data, &tokens)
+	if err != nil {
+		return nil, fmt.Errorf("unable to decode token store %q, reason: %w", s.path, err)
+	}
+	return tokens, nil
+}
+
+// Writes the tokens to the file, replacing it atomically.
+func (s *FileTokenStore) write(tokens map[string]Token) error {
+	data, err := json.MarshalIndent(tokens, "", "  ")
+	if err != nil {
+		return fmt.Errorf("unable to encode token store, reason: %w", err)
+	}
+	// Temporary files are created with 0600 permissions.
+	f, err := os.CreateTemp(filepath.Dir(s.path), filepath.Base(s.path)+".tmp")
+	if err != nil {
+		return fmt.Errorf("unable to write token store %q, reason: %w", s.path, err)
+	}
+	_, err = f.Write(append(data, '\n'))
+	if closeErr := f.Close(); err == nil {
+		err = closeErr
+	}
+	if err == nil {
+		err = os.Rename(f.Name(), s.path)
+	}
+	if err != nil {
+		os.Remove(f.Name())
+		return fmt.Errorf("unable to write token store %q, reason: %w", s.path, err)
+	}
+	return nil
+}
+
+// Returns the key for the token of the specified host and user name.
+func tokenStoreKey(host string, username string) string {
+	return username + "@" + host
+}
@@ -0,0 +1,134 @@
+package cablemodemutil
+
+import (
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/tuxdude/cablemodemutil/cablemodemsim"
+)
+
+func TestFileTokenStore(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "tokens.json")
+	s := NewFileTokenStore(path)
+
+	got, err := s.Load("192.168.100.1", "admin")
+	if err != nil || got != nil {
+		t.Fatalf("Load() from a missing file = (%+v, %v) want: (nil, nil)", got, err)
+	}
+
+	tok := Token{UID: "uid", PrivateKey: "key", Expiry: time.Now().Add(time.Minute).Round(0)}
+	err = s.Save("192.168.100.1", "admin", &tok)
+	if err != nil {
+		t.Fatalf("Save() failed, reason: %s", err)
+	}
+	info, err := os.Stat(path)
+	if err != nil {
+		t.Fatalf("unable to stat the token store, reason: %s", err)
+	}
+	if perm := info.Mode().Perm(); perm != 0o600 {
+		t.Errorf("token store permissions = %o want: 600", perm)
+	}
+
+	got, err = NewFileTokenStore(path).Load("192.168.100.1", "admin")
+	if err != nil || got == nil || got.UID != tok.UID || got.PrivateKey != tok.PrivateKey || !got.Expiry.Equal(tok.Expiry) {
+		t.Errorf("Load() = (%+v, %v) want: (%+v, nil)", got, err, tok)
+	}
+	got, err = s.Load("192.168.100.1", "user")
+	if err != nil || got != nil {
+		t.Errorf("Load() for another user = (%+v, %v) want: (nil, nil)", got, err)
+	}
+
+	err = s.Save("192.168.100.1", "admin", nil)
+	if err != nil {
+		t.Fatalf("Save() failed, reason: %s", err)
+	}
+	got, err = s.Load("192.168.100.1", "admin")
+	if err != nil || got != nil {
+		t.Errorf("Load() after removal = (%+v, %v) want: (nil, nil)", got, err)
+	}
+}
+
+func TestRetrieverTokenStore(t *testing.T) {
+	sim := cablemodemsim.New(&cablemodemsim.Config{
+		Username: testUsername,
+		Password: testPassword,
+	})
+	srv := httptest.NewTLSServer(sim)
+	defer srv.Close()
+	store := NewFileTokenStore(filepath.Join(t.TempDir(), "tokens.json"))
+
+	// Each retriever simulates a separate invocation of a short-lived
+	// process.
+	for i := 0; i < 3; i++ {
+		r := NewStatusRetriever(&RetrieverInput{
+			Host:          strings.TrimPrefix(srv.URL, "https://"),
+			Protocol:      "https",
+			HTTPClient:    srv.Client(),
+			Username:      testUsername,
+			ClearPassword: testPassword,
+			TokenStore:    store,
+		})
+		_, err := r.Status()
+		if err != nil {
+			t.Fatalf("Status() failed, reason: %s", err)
+		}
+	}
+	if got := sim.Logins(); got != 1 {
+		t.Errorf("Logins() = %d want: 1", got)
+	}
+
+	// A stale token in the store results in a fresh login.
+	sim.ExpireSessions()
+	r := NewStatusRetriever(&RetrieverInput{
+		Host:          strings.TrimPrefix(srv.URL, "https://"),
+		Protocol:      "https",
+		HTTPClient:    srv.Client(),
+		Username:      testUsername,
+		ClearPassword: testPassword,
+		TokenStore:    store,
+	})
+	_, err := r.Status()
+	if err != nil {
+		t.Fatalf("Status() with a stale stored token failed, reason: %s", err)
+	}
+	if got := sim.Logins(); got != 2 {
+		t.Errorf("Logins() = %d want: 2", got)
+	}
+}
+
+// A token store counting the saves.
+type countingTokenStore struct {
+	TokenStore
+	saves int
+}
+
+func (s *countingTokenStore) Save(host string, username string, tok *Token) error {
+	s.saves++
+	return s.TokenStore.Save(host, username, tok)
+}
+
+func TestRetrieverTokenStoreSaves(t *testing.T) {
+	r, _ := newTestRetriever(t, testPassword)
+	store := &countingTokenStore{TokenStore: NewFileTokenStore(filepath.Join(t.TempDir(), "tokens.json"))}
+	r.driver.(*arrisDriver).session.tokenStore = store
+
+	// The token is saved only when the session changes, and not for every
+	// request extending the expiry.
+	for i := 0; i < 3; i++ {
+		if _, err := r.Status(); err != nil {
+			t.Fatalf("Status() failed, reason: %s", err)
+		}
+	}
+	if store.saves != 1 {
+		t.Errorf("Save() called %d times want: 1", store.saves)
+	}
+
+	r.driver.(*arrisDriver).session.reset()
+	if store.saves != 2 {
+		t.Errorf("Save() called %d times after reset want: 2", store.saves)
+	}
+}
@@ -0,0 +1,357 @@
+package cablemodemutil
+
+import (
+	"context"
+	"math/rand"
+	"strconv"
+	"time"
+)
+
+const (
+	defaultWatchInterval                  = time.Minute
+	defaultWatchMaxBackoff                = 10 * time.Minute
+	defaultUncorrectedErrorSpikeThreshold = 100
+	watchEventsBufferSize                 = 32
+)
+
+// WatchEventType identifies the type of a change observed by the Watcher.
+type WatchEventType uint8
+
+const (
+	// WatchEventPollFailed indicates a failure in retrieving the status.
+	WatchEventPollFailed WatchEventType = iota
+	// WatchEventLockLost indicates a channel losing lock.
+	WatchEventLockLost
+	// WatchEventLockRegained indicates a channel regaining lock.
+	WatchEventLockRegained
+	// WatchEventChannelAdded indicates a new channel.
+	WatchEventChannelAdded
+	// WatchEventChannelRemoved indicates a channel no longer being present.
+	WatchEventChannelRemoved
+	// WatchEventModulationChanged indicates a change in the modulation of a
+	// channel.
+	WatchEventModulationChanged
+	// WatchEventUncorrectedErrorSpike indicates the uncorrected errors on a
+	// downstream channel increasing by at least the configured threshold
+	// between successive polls.
+	WatchEventUncorrectedErrorSpike
+	// WatchEventInternetConnectivityChanged indicates the internet connection
+	// going down or coming back up.
+	WatchEventInternetConnectivityChanged
+	// WatchEventReboot indicates the cable modem having rebooted, detected by
+	// a decrease in the uptime.
+	WatchEventReboot
+	// WatchEventFirmwareChanged indicates a change in the firmware version.
+	WatchEventFirmwareChanged
+	// WatchEventNewLogEntries indicates new log entries since the previous
+	// poll.
+	WatchEventNewLogEntries
+)
+
+// String returns the string representation of the watch event type.
+func (t WatchEventType) String() string {
+	switch t {
+	case WatchEventPollFailed:
+		return "PollFailed"
+	case WatchEventLockLost:
+		return "LockLost"
+	case WatchEventLockRegained:
+		return "LockRegained"
+	case WatchEventChannelAdded:
+		return "ChannelAdded"
+	case WatchEventChannelRemoved:
+		return "ChannelRemoved"
+	case WatchEventModulationChanged:
+		return "ModulationChanged"
+	case WatchEventUncorrectedErrorSpike:
+		return "UncorrectedErrorSpike"
+	case WatchEventInternetConnectivityChanged:
+		return "InternetConnectivityChanged"
+	case WatchEventReboot:
+		return "Reboot"
+	case WatchEventFirmwareChanged:
+		return "FirmwareChanged"
+	case WatchEventNewLogEntries:
+		return "NewLogEntries"
+	}
+	return "Unknown"
+}
+
+// ChannelDirection identifies the direction of a channel.
+type ChannelDirection uint8
+
+const (
+	// ChannelDownstream identifies a downstream channel.
+	ChannelDownstream ChannelDirection = iota
+	// ChannelUpstream identifies an upstream channel.
+	ChannelUpstream
+)
+
+// String returns the string representation of the channel direction.
+func (d ChannelDirection) String() string {
+	if d == ChannelUpstream {
+		return "Upstream"
+	}
+	return "Downstream"
+}
+
+// WatchEvent contains a change observed by the Watcher.
+type WatchEvent struct {
+	// Type of the change.
+	Type WatchEventType
+	// Time at which the change was observed.
+	ObservedAt time.Time
+	// Direction of the channel for the channel events.
+	Direction ChannelDirection
+	// ID of the channel for the channel events.
+	ChannelID uint32
+	// Previous value for the modulation, firmware version, internet
+	// connectivity and uptime changes.
+	Previous string
+	// Current value for the modulation, firmware version, internet
+	// connectivity and uptime changes.
+	Current string
+	// Increase in the uncorrected errors for the uncorrected error spike.
+	UncorrectedErrors uint32
+	// New log entries since the previous poll.
+	Logs []LogEntry
+	// Status in which the change was observed, nil for poll failures.
+	Status *CableModemStatus
+	// Reason for the poll failure.
+	Err error
+}
+
+// WatcherOptions is used to specify the options of the Watcher.
+type WatcherOptions struct {
+	// Interval between successive polls, defaults to 1 minute.
+	Interval time.Duration
+	// Maximum random delay added to every interval, defaults to a tenth of
+	// the interval.
+	Jitter time.Duration
+	// Maximum interval between polls while backing off on successive
+	// failures, defaults to 10 minutes.
+	MaxBackoff time.Duration
+	// Minimum increase in the uncorrected errors on a downstream channel
+	// between successive polls considered a spike, defaults to 100.
+	UncorrectedErrorSpikeThreshold uint32
+	// Sections of the status to poll, defaults to DefaultSections.
+	Sections StatusSection
+}
+
+// Watcher polls the status from the cable modem and emits the changes
+// between successive polls as events.
+type Watcher struct {
+	retriever *Retriever
+	opts      WatcherOptions
+}
+
+// NewWatcher returns a new watcher polling the status using the specified
+// retriever.
+func NewWatcher(r *Retriever, opts *WatcherOptions) *Watcher {
+	w := Watcher{}
+	w.retriever = r
+	if opts != nil {
+		w.opts = *opts
+	}
+	if w.opts.Interval <= 0 {
+		w.opts.Interval = defaultWatchInterval
+	}
+	if w.opts.Jitter <= 0 {
+		w.opts.Jitter = w.opts.Interval / 10
+	}
+	if w.opts.MaxBackoff < w.opts.Interval {
+		w.opts.MaxBackoff = defaultWatchMaxBackoff
+		if w.opts.MaxBackoff < w.opts.Interval {
+			w.opts.MaxBackoff = w.opts.Interval
+		}
+	}
+	if w.opts.UncorrectedErrorSpikeThreshold == 0 {
+		w.opts.UncorrectedErrorSpikeThreshold = defaultUncorrectedErrorSpikeThreshold
+	}
+	if w.opts.Sections == 0 {
+		w.opts.Sections = DefaultSections
+	}
+	return &w
+}
+
+// Watch starts polling the status in the background until the context is
+// done, and returns the channel on which the events are emitted. The first
+// successful poll only establishes the baseline for the changes. The
+// channel is closed once polling stops. Polling is paused while the caller
+// is not receiving the events.
+func (w *Watcher) Watch(ctx context.Context) <-chan WatchEvent {
+	events := make(chan WatchEvent, watchEventsBufferSize)
+	go w.run(ctx, events)
+	return events
+}
+
+// Polls the status and emits the events until the context is done.
+func (w *Watcher) run(ctx context.Context, events chan<- WatchEvent) {
+	defer close(events)
+
+	opts := StatusOptions{Sections: w.opts.Sections}
+	var prev *CableModemStatus
+	failures := 0
+	for {
+		st, err := w.retriever.StatusWithOptions(ctx, &opts)
+		if ctx.Err() != nil {
+			return
+		}
+
+		var evs []WatchEvent
+		if err != nil {
+			failures++
+			evs = []WatchEvent{{Type: WatchEventPollFailed, ObservedAt: time.Now(), Err: err}}
+		} else {
+			failures = 0
+			if prev != nil {
+				evs = diffStatus(prev, st, w.opts.UncorrectedErrorSpikeThreshold, time.Now())
+			}
+			prev = st
+		}
+		for _, ev := range evs {
+			select {
+			case events <- ev:
+			case <-ctx.Done():
+				return
+			}
+		}
+
+		timer := time.NewTimer(w.nextDelay(failures))
+		select {
+		case <-timer.C:
+		case <-ctx.Done():
+			timer.Stop()
+			return
+		}
+	}
+}
+
+// Returns the delay before the next poll, doubling the interval for every
+// successive failure up to the maximum backoff, along with a random jitter.
+func (w *Watcher) nextDelay(failures int) time.Duration {
+	delay := w.opts.Interval
+	for i := 0; i < failures && delay < w.opts.MaxBackoff; i++ {
+		delay *= 2
+	}
+	if delay > w.opts.MaxBackoff {
+		delay = w.opts.MaxBackoff
+	}
+	// nolint:gosec
+	return delay + time.Duration(rand.Int63n(int64(w.opts.Jitter)+1))
+}
+
+// A channel compared across successive statuses.
+type watchedChannel struct {
+	locked            bool
+	modulation        string
+	uncorrectedErrors uint32
+}
+
+// A key identifying a channel across successive statuses.
+type watchedChannelKey struct {
+	direction ChannelDirection
+	id        uint32
+}
+
+// Returns the channels in the specified status keyed by the direction and
+// the channel ID, along with the keys in the order of the channels.
+func watchedChannels(st *CableModemStatus) (map[watchedChannelKey]watchedChannel, []watchedChannelKey) {
+	res := make(map[watchedChannelKey]watchedChannel)
+	var keys []watchedChannelKey
+	for _, c := range st.Connection.Downstream.Channels {
+		k := watchedChannelKey{direction: ChannelDownstream, id: c.ChannelID}
+		res[k] = watchedChannel{locked: c.Locked, modulation: c.Modulation, uncorrectedErrors: c.UncorrectedErrors}
+		keys = append(keys, k)
+	}
+	for _, c := range st.Connection.Upstream.Channels {
+		k := watchedChannelKey{direction: ChannelUpstream, id: c.ChannelID}
+		res[k] = watchedChannel{locked: c.Locked, modulation: c.Modulation}
+		keys = append(keys, k)
+	}
+	return res, keys
+}
+
+// Returns the events for the changes between the specified successive
+// statuses.
+// nolint:funlen
+func diffStatus(prev *CableModemStatus, cur *CableModemStatus, spikeThreshold uint32, now time.Time) []WatchEvent {
+	var res []WatchEvent
+	event := func(t WatchEventType) WatchEvent {
+		return WatchEvent{Type: t, ObservedAt: now, Status: cur}
+	}
+
+	if cur.Connection.UpTime < prev.Connection.UpTime {
+		ev := event(WatchEventReboot)
+		ev.Previous = prev.Connection.UpTime.String()
+		ev.Current = cur.Connection.UpTime.String()
+		res = append(res, ev)
+	}
+	if prev.Software.FirmwareVersion != "" && cur.Software.FirmwareVersion != "" &&
+		prev.Software.FirmwareVersion != cur.Software.FirmwareVersion {
+		ev := event(WatchEventFirmwareChanged)
+		ev.Previous = prev.Software.FirmwareVersion
+		ev.Current = cur.Software.FirmwareVersion
+		res = append(res, ev)
+	}
+	if prev.Connection.InternetConnected != cur.Connection.InternetConnected {
+		ev := event(WatchEventInternetConnectivityChanged)
+		ev.Previous = strconv.FormatBool(prev.Connection.InternetConnected)
+		ev.Current = strconv.FormatBool(cur.Connection.InternetConnected)
+		res = append(res, ev)
+	}
+
+	prevChannels, prevKeys := watchedChannels(prev)
+	curChannels, curKeys := watchedChannels(cur)
+	channelEvent := func(t WatchEventType, k watchedChannelKey) WatchEvent {
+		ev := event(t)
+		ev.Direction = k.direction
+		ev.ChannelID = k.id
+		return ev
+	}
+	for _, k := range prevKeys {
+		if _, ok := curChannels[k]; !ok {
+			res = append(res, channelEvent(WatchEventChannelRemoved, k))
+		}
+	}
+	for _, k := range curKeys {
+		c := curChannels[k]
+		p, ok := prevChannels[k]
+		if !ok {
+			res = append(res, channelEvent(WatchEventChannelAdded, k))
+			continue
+		}
+		if p.locked && !c.locked {
+			res = append(res, channelEvent(WatchEventLockLost, k))
+		} else if !p.locked && c.locked {
+			res = append(res, channelEvent(WatchEventLockRegained, k))
+		}
+		if p.modulation != c.modulation {
+			ev := channelEvent(WatchEventModulationChanged, k)
+			ev.Previous = p.modulation
+			ev.Current = c.modulation
+			res = append(res, ev)
+		}
+		// A decrease indicates the counters being reset.
+		if c.uncorrectedErrors > p.uncorrectedErrors && c.uncorrectedErrors-p.uncorrectedErrors >= spikeThreshold {
+			ev := channelEvent(WatchEventUncorrectedErrorSpike, k)
+			ev.UncorrectedErrors = c.uncorrectedErrors - p.uncorrectedErrors
+			res = append(res, ev)
+		}
+	}
+
+	if logs := newLogEntries(prev.Logs, cur.Logs); len(logs) > 0 {
+		ev := event(WatchEventNewLogEntries)
+		ev.Logs = logs
+		res = append(res, ev)
+	}
+	return res
+}
+
+// Returns the entries in the current logs newer than the latest entry in the
+// previous logs.
+func newLogEntries(prev []LogEntry, cur []LogEntry) []LogEntry {
+	var c LogCursor
+	c.Next(prev)
+	return c.Next(cur)
+}
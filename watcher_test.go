@@ -0,0 +1,148 @@
+package cablemodemutil
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/tuxdude/cablemodemutil/cablemodemsim"
+)
+
+// Returns a status with the specified uptime and downstream channels.
+func watchTestStatus(upTime time.Duration, ds ...DownstreamChannelInfo) *CableModemStatus {
+	st := CableModemStatus{}
+	st.Software.FirmwareVersion = "TB01.03.001.10"
+	st.Connection.UpTime = upTime
+	st.Connection.InternetConnected = true
+	st.Connection.Downstream.Channels = ds
+	st.Connection.Upstream.Channels = []UpstreamChannelInfo{
+		{Locked: true, ChannelID: 1, Modulation: "SC-QAM"},
+	}
+	return &st
+}
+
+func TestDiffStatus(t *testing.T) {
+	now := time.Now()
+	logTime := time.Date(2021, 10, 23, 8, 15, 20, 0, time.UTC)
+
+	prev := watchTestStatus(
+		time.Hour,
+		DownstreamChannelInfo{Locked: true, ChannelID: 1, Modulation: "QAM256", UncorrectedErrors: 10},
+		DownstreamChannelInfo{Locked: true, ChannelID: 2, Modulation: "QAM256", UncorrectedErrors: 10},
+		DownstreamChannelInfo{Locked: false, ChannelID: 3, Modulation: "QAM256"},
+		DownstreamChannelInfo{Locked: true, ChannelID: 4, Modulation: "QAM256"},
+	)
+	prev.Logs = []LogEntry{{Timestamp: logTime.Add(-time.Hour), Log: "old"}}
+
+	cur := watchTestStatus(
+		30*time.Minute,
+		DownstreamChannelInfo{Locked: false, ChannelID: 1, Modulation: "QAM64", UncorrectedErrors: 10},
+		DownstreamChannelInfo{Locked: true, ChannelID: 2, Modulation: "QAM256", UncorrectedErrors: 110},
+		DownstreamChannelInfo{Locked: true, ChannelID: 3, Modulation: "QAM256"},
+		DownstreamChannelInfo{Locked: true, ChannelID: 5, Modulation: "QAM256"},
+	)
+	cur.Software.FirmwareVersion = "TB01.03.001.12"
+	cur.Connection.InternetConnected = false
+	cur.Logs = []LogEntry{{Timestamp: logTime.Add(-time.Hour), Log: "old"}, {Timestamp: logTime, Log: "new"}}
+
+	want := []WatchEvent{
+		{Type: WatchEventReboot, Previous: "1h0m0s", Current: "30m0s"},
+		{Type: WatchEventFirmwareChanged, Previous: "TB01.03.001.10", Current: "TB01.03.001.12"},
+		{Type: WatchEventInternetConnectivityChanged, Previous: "true", Current: "false"},
+		{Type: WatchEventChannelRemoved, ChannelID: 4},
+		{Type: WatchEventLockLost, ChannelID: 1},
+		{Type: WatchEventModulationChanged, ChannelID: 1, Previous: "QAM256", Current: "QAM64"},
+		{Type: WatchEventUncorrectedErrorSpike, ChannelID: 2, UncorrectedErrors: 100},
+		{Type: WatchEventLockRegained, ChannelID: 3},
+		{Type: WatchEventChannelAdded, ChannelID: 5},
+		{Type: WatchEventNewLogEntries},
+	}
+
+	got := diffStatus(prev, cur, 100, now)
+	if len(got) != len(want) {
+		t.Fatalf("diffStatus() returned %d events want: %d, got: %+v", len(got), len(want), got)
+	}
+	for i, w := range want {
+		g := got[i]
+		if g.Type != w.Type || g.ChannelID != w.ChannelID || g.Previous != w.Previous || g.Current != w.Current ||
+			g.UncorrectedErrors != w.UncorrectedErrors || g.Direction != ChannelDownstream {
+			t.Errorf("diffStatus() event[%d] = %s %+v want: %s %+v", i, g.Type, g, w.Type, w)
+		}
+		if g.Status != cur || !g.ObservedAt.Equal(now) {
+			t.Errorf("diffStatus() event[%d] Status/ObservedAt mismatch", i)
+		}
+	}
+	if logs := got[len(got)-1].Logs; len(logs) != 1 || logs[0].Log != "new" {
+		t.Errorf("diffStatus() new log entries = %+v want only the new entry", logs)
+	}
+
+	if got := diffStatus(cur, cur, 100, now); len(got) != 0 {
+		t.Errorf("diffStatus() for an unchanged status = %+v want: no events", got)
+	}
+}
+
+func TestWatcherWatch(t *testing.T) {
+	r, sim := newTestRetriever(t, testPassword)
+	w := NewWatcher(r, &WatcherOptions{Interval: 10 * time.Millisecond, Jitter: time.Millisecond})
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	events := w.Watch(ctx)
+
+	// Wait for the baseline to be established before changing the firmware.
+	for sim.Logins() == 0 {
+		time.Sleep(time.Millisecond)
+	}
+	time.Sleep(50 * time.Millisecond)
+	sw := cablemodemsim.DefaultResponses()["GetCustomerStatusSoftware"]
+	sw["StatusSoftwareSfVer"] = "TB01.03.001.12"
+	sim.SetResponse("GetCustomerStatusSoftware", sw)
+
+	for ev := range events {
+		if ev.Type != WatchEventFirmwareChanged {
+			t.Fatalf("Watch() emitted unexpected event %s: %+v", ev.Type, ev)
+		}
+		if ev.Current != "TB01.03.001.12" {
+			t.Errorf("Watch() firmware changed to %q want: %q", ev.Current, "TB01.03.001.12")
+		}
+		cancel()
+	}
+	if ctx.Err() != context.Canceled {
+		t.Errorf("Watch() stopped without emitting the firmware change, reason: %v", ctx.Err())
+	}
+}
+
+func TestWatcherWatchPollFailed(t *testing.T) {
+	r, _ := newTestRetriever(t, "wrong-password")
+	w := NewWatcher(r, &WatcherOptions{Interval: 10 * time.Millisecond})
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	ev, ok := <-w.Watch(ctx)
+	if !ok {
+		t.Fatalf("Watch() closed the channel without emitting any event")
+	}
+	if ev.Type != WatchEventPollFailed || ev.Err == nil {
+		t.Errorf("Watch() emitted %s with error %v want: %s with an error", ev.Type, ev.Err, WatchEventPollFailed)
+	}
+}
+
+func TestWatcherNextDelay(t *testing.T) {
+	w := NewWatcher(nil, &WatcherOptions{Interval: time.Second, Jitter: time.Nanosecond, MaxBackoff: 5 * time.Second})
+	tests := []struct {
+		failures int
+		want     time.Duration
+	}{
+		{0, time.Second},
+		{1, 2 * time.Second},
+		{2, 4 * time.Second},
+		{3, 5 * time.Second},
+		{10, 5 * time.Second},
+	}
+	for _, tc := range tests {
+		got := w.nextDelay(tc.failures)
+		if got < tc.want || got > tc.want+time.Nanosecond {
+			t.Errorf("nextDelay(%d) = %s want: %s", tc.failures, got, tc.want)
+		}
+	}
+}